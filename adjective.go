@@ -3,12 +3,33 @@ package lutral
 import "strings"
 
 func AdjectiveFromEntry(entry Entry) *Node {
-	core := *BuildTree(strings.ToLower(entry.Word))
+	word := strings.ToLower(entry.Word)
+	core := *BuildTree(word)
 
-	return CombineTrees(
+	res := CombineTrees(
 		GenerateAdjective(core).AndThenResult(entry.ID),
-		GenerateAdjectiveAdverb(core).AndThenResult(entry.ID),
+		GenerateAdjectiveAdverb(core).AndThenResult(derivedResultValue(entry.ID, "adv.", adjectiveBasePoS(entry), DerivationAdverb)),
 	)
+
+	// Loanwords drop the last ì before the attributive -a as they do before case endings, but not if it's stressed.
+	finalStressed, _ := entry.IsStressedAt(len(word) - 1)
+	if entry.HasFlag("loanword") && strings.HasSuffix(word, "ì") && !finalStressed {
+		res.MergeFrom(*BuildTree(strings.TrimSuffix(word, "ì"), "-a").AndThenResult(entry.ID))
+	}
+
+	return res
+}
+
+// adjectiveBasePoS finds what the entry is an adjective as, since ordinal numerals and interrogatives
+// are inflected as adjectives too.
+func adjectiveBasePoS(entry Entry) string {
+	for _, pos := range []string{"adj.", "num.", "inter."} {
+		if entry.HasPoS(pos) {
+			return pos
+		}
+	}
+
+	return "adj."
 }
 
 func AffixedOnlyAdjectiveFromEntry(entry Entry) *Node {
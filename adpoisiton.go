@@ -1,6 +1,9 @@
 package lutral
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 func AdpositionFromEntry(entry Entry) (res *Node, suffix *Node) {
 	word := strings.TrimSuffix(strings.ToLower(entry.Word), "+")
@@ -13,3 +16,47 @@ func AdpositionFromEntry(entry Entry) (res *Node, suffix *Node) {
 	return BuildTree(word).AndThenResult(resultValue),
 		BuildTree("-"+word, "/return")
 }
+
+// AdpositionPrefixFromEntry generates the adposition as a noun prefix (e.g. fpi+ in fpisìfmetok), followed
+// by the next subtree. The leniting ones (marked with "+") lenite the noun.
+func AdpositionPrefixFromEntry(entry Entry, next string) *Node {
+	word := strings.ToLower(entry.Word)
+	if !strings.HasSuffix(word, "+") {
+		word += "-"
+	}
+
+	return BuildTree(word, "$"+next)
+}
+
+// dropAdpositionCases removes the results after the offset that have an adposition prefix along with other
+// suffixes than -to and -sì (e.g. fpisìfmetokìl), since a noun with one of them takes no case endings.
+func (runner *Runner) dropAdpositionCases(resOffset int) {
+	next := resOffset
+	for i := resOffset; i < len(runner.res); i++ {
+		res := runner.res[i]
+		if runner.hasAdpositionCase(res) {
+			continue
+		}
+
+		runner.res[next] = res
+		next += 1
+	}
+
+	runner.res = runner.res[:next]
+}
+
+func (runner *Runner) hasAdpositionCase(res Result) bool {
+	for _, prefix := range res.Prefixes {
+		if !slices.Contains(runner.Adpositions, prefix) {
+			continue
+		}
+
+		for _, suffix := range res.Suffixes {
+			if suffix != "to" && suffix != "sì" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -2,17 +2,58 @@ package lutral
 
 import "strings"
 
+// Spelling is the spelling variant a result was found with.
+type Spelling string
+
+const (
+	SpellingStandard Spelling = "standard"
+	// SpellingCluster is sä- or tìs- merged with the consonant after, e.g. spxìm for säpxìm.
+	SpellingCluster Spelling = "cluster"
+	// SpellingReef is the Reef dialect's spelling, e.g. ganì for kxanì.
+	SpellingReef Spelling = "reef"
+	// SpellingReefCluster is the Reef spelling of a SpellingCluster variant.
+	SpellingReefCluster Spelling = "reef-cluster"
+	// SpellingContraction is fut- contracted to ft-.
+	SpellingContraction Spelling = "contraction"
+)
+
+// IsReef returns whether the spelling is from the Reef dialect.
+func (spelling Spelling) IsReef() bool {
+	return spelling == SpellingReef || spelling == SpellingReefCluster
+}
+
+// SpellingVariant is a spelling of a word, along with what kind of variant it is.
+type SpellingVariant struct {
+	Text     string
+	Spelling Spelling
+}
+
 func WithAlternativeSpellings(str string) []string {
 	return append([]string{str}, AlternativeSpellings(str)...)
 }
 
 func AlternativeSpellings(str string) []string {
 	var res []string
+	for _, variant := range AlternativeSpellingVariants(str) {
+		res = append(res, variant.Text)
+	}
+
+	return res
+}
+
+// WithAlternativeSpellingVariants is like WithAlternativeSpellings, but it tells the variants apart.
+func WithAlternativeSpellingVariants(str string) []SpellingVariant {
+	return append([]SpellingVariant{{Text: str, Spelling: SpellingStandard}}, AlternativeSpellingVariants(str)...)
+}
+
+// AlternativeSpellingVariants is like AlternativeSpellings, but it tells the variants apart.
+func AlternativeSpellingVariants(str string) []SpellingVariant {
+	var res []SpellingVariant
 	if strings.HasPrefix(str, "sä") && !strings.HasPrefix(str, "säts") {
 		afterPrefix := strings.TrimPrefix(str, "sä")
 		for _, clusterable := range clusterables {
 			if strings.HasPrefix(afterPrefix, clusterable) {
-				res = append(res, "s"+afterPrefix)
+				res = append(res, SpellingVariant{Text: "s" + afterPrefix, Spelling: SpellingCluster})
 				break
 			}
 		}
@@ -22,7 +63,7 @@ func AlternativeSpellings(str string) []string {
 		afterPrefix := strings.TrimPrefix(str, "tìs")
 		for _, clusterable := range clusterables {
 			if strings.HasPrefix(afterPrefix, clusterable) {
-				res = append(res, "ts"+afterPrefix)
+				res = append(res, SpellingVariant{Text: "ts" + afterPrefix, Spelling: SpellingCluster})
 				break
 			}
 		}
@@ -30,23 +71,38 @@ func AlternativeSpellings(str string) []string {
 
 	beforeReef := len(res)
 	if reefAlt := generateReefSpelling(str); reefAlt != str {
-		res = append(res, reefAlt)
+		res = append(res, SpellingVariant{Text: reefAlt, Spelling: SpellingReef})
 	}
 
 	for _, alt := range res[:beforeReef] {
-		reefAlt := generateReefSpelling(alt)
-		if reefAlt != alt {
-			res = append(res, reefAlt)
+		reefAlt := generateReefSpelling(alt.Text)
+		if reefAlt != alt.Text {
+			res = append(res, SpellingVariant{Text: reefAlt, Spelling: SpellingReefCluster})
 		}
 	}
 
 	if strings.HasPrefix(str, "fut") && !strings.HasPrefix(str, "futs") {
-		res = append(res, "ft"+str[len("fut"):])
+		res = append(res, SpellingVariant{Text: "ft" + str[len("fut"):], Spelling: SpellingContraction})
 	}
 
 	return res
 }
 
+// tagSpelling adds the spelling to the result nodes in the tree, unless it's the standard spelling.
+func tagSpelling(tree *Node, spelling Spelling) {
+	if spelling == SpellingStandard {
+		return
+	}
+
+	tree.SearchReplace(func(node *Node) *Node {
+		if node.Kind == NKResult {
+			node.Value += "@" + string(spelling)
+		}
+
+		return nil
+	})
+}
+
 func generateReefSpelling(s string) string {
 	res := reefReplacer.Replace(s)
 	if strings.ContainsRune(res, '\'') {
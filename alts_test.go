@@ -32,3 +32,16 @@ func TestAlternativeSpellings(t *testing.T) {
 		})
 	}
 }
+
+func TestAlternativeSpellingVariants(t *testing.T) {
+	assert.Equal(t, []SpellingVariant{
+		{Text: "tìsyìmawnun'i", Spelling: SpellingStandard},
+		{Text: "tsyìmawnun'i", Spelling: SpellingCluster},
+		{Text: "tìshìmawnun'i", Spelling: SpellingReef},
+		{Text: "chìmawnun'i", Spelling: SpellingReefCluster},
+	}, WithAlternativeSpellingVariants("tìsyìmawnun'i"))
+	assert.Equal(t, []SpellingVariant{
+		{Text: "futa", Spelling: SpellingStandard},
+		{Text: "fta", Spelling: SpellingContraction},
+	}, WithAlternativeSpellingVariants("futa"))
+}
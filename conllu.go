@@ -0,0 +1,371 @@
+package lutral
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Lemma is what the CoNLL-U export and ResultIPA need to know about an entry.
+type Lemma struct {
+	Word      string   `json:"word"`
+	PoS       []string `json:"pos"`
+	Syllables []int    `json:"syllables,omitempty"`
+	Stress    *int     `json:"stress,omitempty"`
+}
+
+// WriteCoNLLU writes the results of Extract over one sentence as a CoNLL-U sentence, with one token for
+// each position. The first result at a position is the token's reading, and the others are listed in MISC
+// as Alt1, Alt2, etc. in the form "ID:UPOS:FEATS" with the features separated by ";". The lemmas are
+// looked up by result ID, and the word is left as "_" if it's not there.
+//
+// A result of more than one word (e.g. a phrase or multi-word noun) is written as one token for each word,
+// with "Phrase=ID" in MISC instead of "Entry=ID". The first of them has the FEATS and the rest of MISC, and
+// the lemma is split across them if the words line up.
+//
+// The features that have no counterpart in Universal Dependencies use language-specific values, e.g.
+// Case=Top for the topical and Affect=Laud for the laudative.
+func WriteCoNLLU(w io.Writer, results []Result, lemmas map[string]Lemma) error {
+	bw := bufio.NewWriter(w)
+
+	token := 0
+	for i := 0; i < len(results); {
+		next := i + 1
+		for next < len(results) && results[next].Position == results[i].Position {
+			next += 1
+		}
+
+		token = writeCoNLLUToken(bw, token+1, results[i:next], lemmas)
+		i = next
+	}
+	bw.WriteString("\n")
+
+	return bw.Flush()
+}
+
+// WriteCoNLLU writes the results with the dictionary's lemmas, see WriteCoNLLU.
+func (dictionary *Dictionary) WriteCoNLLU(w io.Writer, results []Result) error {
+	return WriteCoNLLU(w, results, dictionary.Lemmas)
+}
+
+// WriteCoNLLUDocument splits the text into sentences and writes each of them as a CoNLL-U sentence with
+// the sent_id and text comments. Unlike Extract, the words that are not found are kept as tokens with
+// the UPOS X.
+func (dictionary *Dictionary) WriteCoNLLUDocument(w io.Writer, text string) error {
+	runner := dictionary.Runner()
+	runner.keepUnknown = true
+
+	for i, sentence := range splitSentences(text) {
+		results, err := runner.ExtractContext(context.Background(), sentence)
+		if err != nil {
+			return err
+		}
+
+		text := strings.Join(strings.Fields(sentence), " ")
+		if _, err := fmt.Fprintf(w, "# sent_id = %d\n# text = %s\n", i+1, text); err != nil {
+			return err
+		}
+		if err := dictionary.WriteCoNLLU(w, results); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeCoNLLUToken writes the token starting at the given ID, and returns the ID of its last word.
+func writeCoNLLUToken(w *bufio.Writer, token int, results []Result, lemmas map[string]Lemma) int {
+	result := results[0]
+
+	forms := strings.Fields(result.Form)
+	if len(forms) == 0 {
+		forms = []string{"_"}
+	}
+	lemma := "_"
+	if entry, ok := lemmas[result.ID]; ok {
+		lemma = entry.Word
+	} else if result.ID == NumeralID && result.Number != nil {
+		lemma = strconv.Itoa(*result.Number)
+	}
+
+	misc := make([]string, 0, len(results)+1)
+	if result.ID != "" && len(forms) > 1 {
+		misc = append(misc, "Phrase="+result.ID)
+	} else if result.ID != "" {
+		misc = append(misc, "Entry="+result.ID)
+	}
+	if result.Derivation != "" {
+		misc = append(misc, "Derivation="+result.Derivation)
+	}
+	if result.Spelling != "" && result.Spelling != SpellingStandard {
+		misc = append(misc, "Spelling="+string(result.Spelling))
+	}
+	for i, alt := range results[1:] {
+		feats := strings.ReplaceAll(coNLLUFeats(alt), "|", ";")
+		misc = append(misc, fmt.Sprintf("Alt%d=%s:%s:%s", i+1, alt.ID, coNLLUPoS(alt, lemmas), feats))
+	}
+
+	if len(forms) == 1 {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t_\t%s\t_\t_\t_\t%s\n",
+			token, forms[0], lemma, coNLLUPoS(result, lemmas), coNLLUFeats(result), orUnderscore(strings.Join(misc, "|")),
+		)
+
+		return token
+	}
+
+	// The words of a phrase are tokens of their own, with the phrase in MISC. The features go on the first.
+	upos := coNLLUPoS(result, lemmas)
+	wordLemmas := coNLLUWordLemmas(forms, lemma, result.Particles)
+	for i, form := range forms {
+		wordUPoS, feats, wordMisc := upos, "_", "Phrase="+result.ID
+		if wordLemmas[i] != "_" && slices.Contains(result.Particles, wordLemmas[i]) {
+			wordUPoS = "PART"
+		}
+		if i == 0 {
+			feats, wordMisc = coNLLUFeats(result), orUnderscore(strings.Join(misc, "|"))
+		}
+		if result.ID == "" {
+			wordMisc = "_"
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t_\t%s\t_\t_\t_\t%s\n", token+i, form, wordLemmas[i], wordUPoS, feats, wordMisc)
+	}
+
+	return token + len(forms) - 1
+}
+
+// coNLLUWordLemmas splits the lemma of a phrase across its words. The particles that go between the words
+// (e.g. ke in "eltut ke heykahaw") are their own lemmas, and the words are "_" if the rest do not line up.
+func coNLLUWordLemmas(forms []string, lemma string, particles []string) []string {
+	lemmaWords := strings.Fields(lemma)
+	res := make([]string, len(forms))
+	next := 0
+	for i, form := range forms {
+		form = strings.ToLower(form)
+		if len(forms)-i > len(lemmaWords)-next && slices.Contains(particles, form) {
+			res[i] = form
+		} else if next < len(lemmaWords) {
+			res[i] = lemmaWords[next]
+			next += 1
+		}
+	}
+
+	if next != len(lemmaWords) {
+		for i := range res {
+			if !slices.Contains(particles, res[i]) {
+				res[i] = "_"
+			}
+		}
+	}
+	for i := range res {
+		if res[i] == "" {
+			res[i] = "_"
+		}
+	}
+
+	return res
+}
+
+// coNLLUPoS finds the UPOS from the result's PoS, or the entry's first PoS if the result has none.
+func coNLLUPoS(result Result, lemmas map[string]Lemma) string {
+	pos := result.PoS
+	if pos == "" {
+		if entry, ok := lemmas[result.ID]; ok && len(entry.PoS) > 0 {
+			pos = entry.PoS[0]
+		} else if result.Number != nil {
+			pos = "num."
+		}
+	}
+
+	if upos, ok := uposMap[pos]; ok {
+		return upos
+	}
+
+	return "X"
+}
+
+// coNLLUFeats builds the FEATS column from Features, the lenitions and the derivation.
+func coNLLUFeats(result Result) string {
+	features := result.Features()
+	feats := make(map[string][]string)
+	add := func(name, value string) {
+		if value != "" && !slices.Contains(feats[name], value) {
+			feats[name] = append(feats[name], value)
+		}
+	}
+
+	add("Case", udCases[features.Case])
+	add("Number", udNumbers[features.Number])
+	add("Tense", udTenses[features.Tense])
+	add("Aspect", udAspects[features.Aspect])
+	add("Mood", udMoods[features.Mood])
+	switch features.Voice {
+	case VoiceReflexive:
+		add("Reflex", "Yes")
+	case VoiceCausative:
+		add("Voice", "Cau")
+	case VoiceReflexiveCausative:
+		add("Reflex", "Yes")
+		add("Voice", "Cau")
+	case VoiceActive:
+		add("Voice", "Act")
+	case VoicePassive:
+		add("Voice", "Pass")
+	}
+	switch features.Affect {
+	case AffectLaudative:
+		add("Affect", "Laud")
+	case AffectPejorative:
+		add("Affect", "Pej")
+	case AffectCeremonial:
+		add("Polite", "Form")
+	}
+	if features.Evidential == EvidentialInferential {
+		add("Evident", "Nfh")
+	}
+	switch features.Determiner {
+	case DeterminerProximal:
+		add("Deixis", "Prox")
+	case DeterminerDistal:
+		add("Deixis", "Remt")
+	case DeterminerUniversal:
+		add("PronType", "Tot")
+	case DeterminerIndefinite:
+		add("PronType", "Ind")
+	}
+	if features.Negated {
+		add("Polarity", "Neg")
+	}
+	if features.Question {
+		add("PronType", "Int")
+	}
+	if len(result.Lenitions) > 0 {
+		add("Lenited", "Yes")
+	}
+	if result.Number != nil {
+		if len(result.Suffixes) > 0 && result.Suffixes[0] == "ve" {
+			add("NumType", "Ord")
+		} else {
+			add("NumType", "Card")
+		}
+	}
+	switch result.Derivation {
+	case DerivationGerund:
+		add("VerbForm", "Ger")
+	case DerivationActiveParticiple, DerivationPassiveParticiple:
+		add("VerbForm", "Part")
+	}
+
+	if len(feats) == 0 {
+		return "_"
+	}
+
+	// CoNLL-U wants the features sorted by name without regard to case, and the values sorted too.
+	names := make([]string, 0, len(feats))
+	for name := range feats {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		values := feats[name]
+		slices.Sort(values)
+		parts = append(parts, name+"="+strings.Join(values, ","))
+	}
+
+	return strings.Join(parts, "|")
+}
+
+// splitSentences splits the text after each run of ".", "?", "!" or "…", leaving out the empty sentences.
+func splitSentences(text string) []string {
+	res := make([]string, 0, 4)
+	add := func(sentence string) {
+		sentence = strings.TrimSpace(sentence)
+		if strings.IndexFunc(sentence, unicode.IsLetter) != -1 {
+			res = append(res, sentence)
+		}
+	}
+
+	start := 0
+	wasEnd := false
+	for i, ch := range text {
+		isEnd := strings.ContainsRune(sentenceTerminators, ch)
+		if wasEnd && !isEnd {
+			add(text[start:i])
+			start = i
+		}
+		wasEnd = isEnd
+	}
+	add(text[start:])
+
+	return res
+}
+
+// sentenceTerminators are the punctuation that ends a sentence.
+const sentenceTerminators = ".?!…"
+
+func orUnderscore(s string) string {
+	if s == "" {
+		return "_"
+	}
+
+	return s
+}
+
+var uposMap = map[string]string{
+	"n.":      "NOUN",
+	"prop.n.": "PROPN",
+	"pn.":     "PRON",
+	"inter.":  "PRON",
+	"adj.":    "ADJ",
+	"num.":    "NUM",
+	"vin.":    "VERB",
+	"vim.":    "VERB",
+	"vtr.":    "VERB",
+	"vtrm.":   "VERB",
+	"adv.":    "ADV",
+	"adp.":    "ADP",
+	"conj.":   "CCONJ",
+	"intj.":   "INTJ",
+	"part.":   "PART",
+}
+
+var udCases = map[Case]string{
+	CaseAgentive:   "Erg",
+	CasePatientive: "Acc",
+	CaseDative:     "Dat",
+	CaseGenitive:   "Gen",
+	CaseTopical:    "Top",
+}
+
+var udNumbers = map[Number]string{
+	NumberDual:   "Dual",
+	NumberTrial:  "Tri",
+	NumberPlural: "Plur",
+}
+
+var udTenses = map[Tense]string{
+	TensePast:       "Past",
+	TenseRecentPast: "Rec",
+	TenseNearFuture: "Near",
+	TenseFuture:     "Fut",
+}
+
+var udAspects = map[Aspect]string{
+	AspectPerfective:   "Perf",
+	AspectImperfective: "Imp",
+}
+
+var udMoods = map[Mood]string{
+	MoodSubjunctive: "Sub",
+	MoodIntentional: "Prp",
+	MoodImperative:  "Imp",
+}
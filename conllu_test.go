@@ -0,0 +1,66 @@
+package lutral
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDictionary_WriteCoNLLU(t *testing.T) {
+	dict := miniDict()
+
+	table := []struct {
+		Text     string
+		Expected []string
+	}{
+		{"Kaltxì, ma kifkey!", []string{
+			"1\tKaltxì\tkaltxì\tINTJ\t_\t_\t_\t_\t_\tEntry=692",
+			"2\tma\tma\tPART\t_\t_\t_\t_\t_\tEntry=1056",
+			"3\tkifkey\tkifkey\tNOUN\t_\t_\t_\t_\t_\tEntry=800",
+		}},
+		{"fmäpetok to tìtseri", []string{
+			"1\tfmäpetok\tfmetok\tVERB\t_\tReflex=Yes\t_\t_\t_\tEntry=392",
+			"2\tto\tto\tX\t_\t_\t_\t_\t_\tPhrase=11608",
+			"3\ttìtseri\ttìtseri\tX\t_\t_\t_\t_\t_\tPhrase=11608",
+		}},
+		{"tsayuvane ukìl", []string{
+			"1\ttsayuvane\tuvan\tNOUN\t_\tCase=Gen|Deixis=Remt|Number=Plur\t_\t_\t_\tEntry=2644|Alt1=2644:NOUN:Deixis=Remt;Number=Plur",
+			"2\tukìl\tuk\tNOUN\t_\tCase=Erg\t_\t_\t_\tEntry=6680",
+		}},
+		{"te'lanti mune", []string{
+			"1\tte'lanti\ttxe'lan\tNOUN\t_\tCase=Acc|Lenited=Yes\t_\t_\t_\tEntry=2512",
+			"2\tmune\tmune\tNUM\t_\tNumType=Card\t_\t_\t_\tEntry=1176",
+		}},
+	}
+
+	for _, row := range table {
+		t.Run(row.Text, func(t *testing.T) {
+			sb := strings.Builder{}
+			assert.NoError(t, dict.WriteCoNLLU(&sb, dict.Extract(row.Text)))
+			assert.Equal(t, strings.Join(row.Expected, "\n")+"\n\n", sb.String())
+		})
+	}
+}
+
+func TestDictionary_WriteCoNLLUDocument(t *testing.T) {
+	dict := miniDict()
+
+	sb := strings.Builder{}
+	assert.NoError(t, dict.WriteCoNLLUDocument(&sb, "Fraeltut ke heykahängaw ukìl. Glurb\ttìtseri\n  fmäpetok?! "))
+	assert.Equal(t, strings.Join([]string{
+		"# sent_id = 1",
+		"# text = Fraeltut ke heykahängaw ukìl.",
+		"1\tFraeltut\teltut\tX\t_\tAffect=Pej|Polarity=Neg|PronType=Tot\t_\t_\t_\tPhrase=13458",
+		"2\tke\tke\tPART\t_\t_\t_\t_\t_\tPhrase=13458",
+		"3\theykahängaw\theykahaw\tX\t_\t_\t_\t_\t_\tPhrase=13458",
+		"4\tukìl\tuk\tNOUN\t_\tCase=Erg\t_\t_\t_\tEntry=6680",
+		"",
+		"# sent_id = 2",
+		"# text = Glurb tìtseri fmäpetok?!",
+		"1\tGlurb\t_\tX\t_\t_\t_\t_\t_\t_",
+		"2\ttìtseri\ttìtseri\tNOUN\t_\t_\t_\t_\t_\tEntry=10368",
+		"3\tfmäpetok\tfmetok\tVERB\t_\tReflex=Yes\t_\t_\t_\tEntry=392",
+		"",
+	}, "\n")+"\n", sb.String())
+}
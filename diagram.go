@@ -0,0 +1,230 @@
+package lutral
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DiagramOptions controls what WriteDOT and WriteMermaid draws.
+type DiagramOptions struct {
+	// SubTrees, if set, draws the subtrees that NKSubTree nodes refer to, with a dashed edge to them.
+	SubTrees map[string]*Node
+	// MaxDepth cuts off the tree below this depth. Zero means no limit.
+	MaxDepth int
+	// ResultID only draws the branches that lead to a result with this ID (e.g. "392").
+	ResultID string
+}
+
+// WriteDOT writes the tree as a Graphviz DOT graph.
+func (node *Node) WriteDOT(w io.Writer, opts DiagramOptions) error {
+	d := newDiagram(opts)
+	d.add(node, "", "", 0, true)
+
+	sb := strings.Builder{}
+	sb.WriteString("digraph tree {\n")
+	sb.WriteString("\tnode [fontname=\"monospace\"];\n")
+	for _, group := range d.groups {
+		indent := "\t"
+		if group != "" {
+			_, _ = fmt.Fprintf(&sb, "\tsubgraph %s {\n", dotQuote("cluster_"+group))
+			_, _ = fmt.Fprintf(&sb, "\t\tlabel=%s;\n", dotQuote("$"+group))
+			indent = "\t\t"
+		}
+
+		for _, dn := range d.nodes {
+			if dn.group == group {
+				shape, ok := dotShapes[dn.kind]
+				if !ok {
+					shape = "ellipse"
+				}
+
+				_, _ = fmt.Fprintf(&sb, "%s%s [label=%s, shape=%s];\n", indent, dn.id, dotQuote(dn.label), shape)
+			}
+		}
+
+		if group != "" {
+			sb.WriteString("\t}\n")
+		}
+	}
+	for _, edge := range d.edges {
+		if edge.subTree {
+			_, _ = fmt.Fprintf(&sb, "\t%s -> %s [style=dashed];\n", edge.from, edge.to)
+		} else {
+			_, _ = fmt.Fprintf(&sb, "\t%s -> %s;\n", edge.from, edge.to)
+		}
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteMermaid writes the tree as a Mermaid flowchart.
+func (node *Node) WriteMermaid(w io.Writer, opts DiagramOptions) error {
+	d := newDiagram(opts)
+	d.add(node, "", "", 0, true)
+
+	sb := strings.Builder{}
+	sb.WriteString("flowchart TD\n")
+	for i, group := range d.groups {
+		indent := "\t"
+		if group != "" {
+			_, _ = fmt.Fprintf(&sb, "\tsubgraph g%d [%s]\n", i, mermaidQuote("$"+group))
+			indent = "\t\t"
+		}
+
+		for _, dn := range d.nodes {
+			if dn.group == group {
+				shape, ok := mermaidShapes[dn.kind]
+				if !ok {
+					shape = [2]string{"[", "]"}
+				}
+
+				_, _ = fmt.Fprintf(&sb, "%s%s%s%s%s\n", indent, dn.id, shape[0], mermaidQuote(dn.label), shape[1])
+			}
+		}
+
+		if group != "" {
+			sb.WriteString("\tend\n")
+		}
+	}
+	for _, edge := range d.edges {
+		if edge.subTree {
+			_, _ = fmt.Fprintf(&sb, "\t%s -.-> %s\n", edge.from, edge.to)
+		} else {
+			_, _ = fmt.Fprintf(&sb, "\t%s --> %s\n", edge.from, edge.to)
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+type diagramNode struct {
+	id    string
+	group string
+	kind  NodeKind
+	label string
+}
+
+type diagramEdge struct {
+	from, to string
+	subTree  bool
+}
+
+type diagram struct {
+	opts     DiagramOptions
+	nodes    []diagramNode
+	edges    []diagramEdge
+	groups   []string
+	subTrees map[string]string
+}
+
+func newDiagram(opts DiagramOptions) *diagram {
+	return &diagram{opts: opts, groups: []string{""}, subTrees: make(map[string]string)}
+}
+
+// add draws the node and its children with an edge from the parent. The result filter only applies
+// outside subtrees, since they never contain any results themselves.
+func (d *diagram) add(node *Node, parentID string, group string, depth int, filter bool) {
+	id := fmt.Sprintf("n%d", len(d.nodes))
+	d.nodes = append(d.nodes, diagramNode{id: id, group: group, kind: node.Kind, label: node.String()})
+	if parentID != "" {
+		d.edges = append(d.edges, diagramEdge{from: parentID, to: id})
+	}
+
+	if node.Kind == NKSubTree && d.opts.SubTrees != nil {
+		d.addSubTree(node.Value, id)
+	}
+
+	if d.opts.MaxDepth > 0 && depth >= d.opts.MaxDepth {
+		return
+	}
+
+	for i := range node.Children {
+		child := &node.Children[i]
+		if filter && d.opts.ResultID != "" && !leadsToResult(child, d.opts.ResultID) {
+			continue
+		}
+
+		d.add(child, id, group, depth+1, filter)
+	}
+}
+
+// addSubTree draws the subtree the first time it's referred to, and a dashed edge to it every time.
+func (d *diagram) addSubTree(name string, fromID string) {
+	id, seen := d.subTrees[name]
+	if !seen {
+		subTree := d.opts.SubTrees[name]
+		if subTree == nil {
+			d.subTrees[name] = ""
+			return
+		}
+
+		// Reserve the name before drawing it, since subtrees can refer to themselves.
+		id = fmt.Sprintf("n%d", len(d.nodes))
+		d.subTrees[name] = id
+		d.edges = append(d.edges, diagramEdge{from: fromID, to: id, subTree: true})
+		d.groups = append(d.groups, name)
+		d.add(subTree, "", name, 0, false)
+
+		return
+	}
+
+	if id != "" {
+		d.edges = append(d.edges, diagramEdge{from: fromID, to: id, subTree: true})
+	}
+}
+
+func leadsToResult(node *Node, id string) bool {
+	if node.Kind == NKResult {
+		return parseResultValue(node.Value).ID == id
+	}
+
+	for i := range node.Children {
+		if leadsToResult(&node.Children[i], id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
+
+var dotShapes = map[NodeKind]string{
+	NKRoot:      "circle",
+	NKResult:    "doubleoctagon",
+	NKRaw:       "box",
+	NKPrefix:    "rarrow",
+	NKInfix:     "diamond",
+	NKSuffix:    "larrow",
+	NKSubTree:   "component",
+	NKReturn:    "invhouse",
+	NKLeafHook:  "point",
+	NKParticle:  "hexagon",
+	NKNumeral:   "octagon",
+	NKIrregular: "note",
+}
+
+var mermaidShapes = map[NodeKind][2]string{
+	NKRoot:      {"((", "))"},
+	NKResult:    {"([", "])"},
+	NKRaw:       {"[", "]"},
+	NKPrefix:    {">", "]"},
+	NKInfix:     {"{", "}"},
+	NKSuffix:    {"[/", "/]"},
+	NKSubTree:   {"[[", "]]"},
+	NKReturn:    {"[\\", "\\]"},
+	NKLeafHook:  {"(", ")"},
+	NKParticle:  {"{{", "}}"},
+	NKNumeral:   {"[(", ")]"},
+	NKIrregular: {"[/", "\\]"},
+}
@@ -0,0 +1,105 @@
+package lutral
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestNode_WriteDOT(t *testing.T) {
+	tree := CombineTrees(
+		BuildTree("$np2", "uvan", "=2644"),
+		BuildTree("utral", "=2628"),
+	)
+	subTrees := map[string]*Node{
+		"np2": BuildTree("fne-", "/return"),
+	}
+
+	sb := strings.Builder{}
+	assert.NoError(t, tree.WriteDOT(&sb, DiagramOptions{SubTrees: subTrees}))
+	assert.Equal(t, `digraph tree {
+	node [fontname="monospace"];
+	n0 [label="/root", shape=circle];
+	n1 [label="$np2", shape=component];
+	n5 [label="uvan", shape=box];
+	n6 [label="=2644", shape=doubleoctagon];
+	n7 [label="utral", shape=box];
+	n8 [label="=2628", shape=doubleoctagon];
+	subgraph "cluster_np2" {
+		label="$np2";
+		n2 [label="/root", shape=circle];
+		n3 [label="fne-", shape=rarrow];
+		n4 [label="/return", shape=invhouse];
+	}
+	n0 -> n1;
+	n1 -> n2 [style=dashed];
+	n2 -> n3;
+	n3 -> n4;
+	n1 -> n5;
+	n5 -> n6;
+	n0 -> n7;
+	n7 -> n8;
+}
+`, sb.String())
+
+	sb.Reset()
+	assert.NoError(t, tree.WriteDOT(&sb, DiagramOptions{ResultID: "2628"}))
+	assert.Equal(t, `digraph tree {
+	node [fontname="monospace"];
+	n0 [label="/root", shape=circle];
+	n1 [label="utral", shape=box];
+	n2 [label="=2628", shape=doubleoctagon];
+	n0 -> n1;
+	n1 -> n2;
+}
+`, sb.String())
+
+	sb.Reset()
+	tree = CombineTrees(
+		BuildTree("fne", "=2644"),
+		BuildTree("utral", "=2628:n.@reef"),
+	)
+	assert.NoError(t, tree.WriteDOT(&sb, DiagramOptions{ResultID: "2628"}))
+	assert.Equal(t, `digraph tree {
+	node [fontname="monospace"];
+	n0 [label="/root", shape=circle];
+	n1 [label="utral", shape=box];
+	n2 [label="=2628:n.@reef", shape=doubleoctagon];
+	n0 -> n1;
+	n1 -> n2;
+}
+`, sb.String())
+}
+
+func TestNode_WriteMermaid(t *testing.T) {
+	tree := CombineTrees(
+		BuildTree("tì-", "fm", "<us>", "etok", "=392:n."),
+		BuildTree("fm", "<0>", "<1>", "et", "<2>", "ok", "=392"),
+	)
+
+	sb := strings.Builder{}
+	assert.NoError(t, tree.WriteMermaid(&sb, DiagramOptions{MaxDepth: 2}))
+	assert.Equal(t, `flowchart TD
+	n0(("/root"))
+	n1>"tì-"]
+	n2["fm"]
+	n3["fm"]
+	n4{"<0>"}
+	n0 --> n1
+	n1 --> n2
+	n0 --> n3
+	n3 --> n4
+`, sb.String())
+
+	sb.Reset()
+	assert.NoError(t, BuildTree("aylan", "~ay+ -yä", "=-1").WriteMermaid(&sb, DiagramOptions{}))
+	assert.Equal(t, `flowchart TD
+	n0(("/root"))
+	n1["aylan"]
+	n2[/"~ay+ -yä"\]
+	n3(["=-1"])
+	n0 --> n1
+	n1 --> n2
+	n2 --> n3
+`, sb.String())
+}
@@ -1,6 +1,10 @@
 package lutral
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -11,90 +15,232 @@ type Dictionary struct {
 	IsSorted   bool                `json:"isSorted"`
 	SubTreeMap map[string]*Node    `json:"subtreeMap"`
 	Phrases    map[string][]Result `json:"phrases"`
+	// Numerals maps the values of the numerals listed as entries to their result values.
+	Numerals map[int]string `json:"numerals,omitempty"`
+	// Irregulars lists the results of the irregular forms for each entry ID, see IrregularForm.
+	Irregulars map[string][]Result `json:"irregulars,omitempty"`
+	// Adpositions lists the adpositions as they are written as affixes, without the "+".
+	Adpositions []string `json:"adpositions,omitempty"`
+	// Lemmas has the word, PoS and stress of each entry if KeepLemmas is set, see KeepLemmas.
+	Lemmas map[string]Lemma `json:"lemmas,omitempty"`
+	// InfixLists overrides or adds to the default infix lists, see Grammar.
+	InfixLists map[string][]string `json:"infixLists,omitempty"`
+
+	// MaxStepCount is passed on to the runners, see Runner.MaxStepCount.
+	MaxStepCount int64 `json:"-"`
+	// Normalize and FoldDiacritics are passed on to the runners, see Runner.Normalize.
+	Normalize      func(text string) string `json:"-"`
+	FoldDiacritics bool                     `json:"-"`
+	// StrictForest and Tokenizer are passed on to the runners, see Runner.StrictForest and Runner.Tokenizer.
+	StrictForest bool      `json:"-"`
+	Tokenizer    Tokenizer `json:"-"`
+	// Scorer is passed on to the runners, see Runner.Scorer.
+	Scorer Scorer `json:"-"`
+	// SkipArchaic makes Insert leave out the entries with the "archaic" flag.
+	SkipArchaic bool `json:"-"`
+	// KeepLemmas makes Insert keep a Lemma for each entry in Lemmas. The phrase slots need them to check
+	// the results without a PoS, and the CoNLL-U export and ResultIPA need them for the words and stress.
+	KeepLemmas bool `json:"-"`
+
+	infixMap map[string][]infix
 }
 
 func (dictionary *Dictionary) Runner() *Runner {
-	return &Runner{Root: &dictionary.Root, SubtreeMap: dictionary.SubTreeMap, PhraseMap: dictionary.Phrases, res: make([]Result, 0, 8), isSorted: dictionary.IsSorted}
+	return &Runner{Root: &dictionary.Root, NumeralRoot: numeralTree, SubtreeMap: dictionary.SubTreeMap, PhraseMap: dictionary.Phrases, NumeralMap: dictionary.Numerals, IrregularMap: dictionary.Irregulars, LemmaMap: dictionary.Lemmas, Adpositions: dictionary.Adpositions, MaxStepCount: dictionary.MaxStepCount, Normalize: dictionary.Normalize, FoldDiacritics: dictionary.FoldDiacritics, StrictForest: dictionary.StrictForest, Tokenizer: dictionary.Tokenizer, Scorer: dictionary.Scorer, res: make([]Result, 0, 8), isSorted: dictionary.IsSorted, infixMap: dictionary.compiledInfixMap()}
+}
+
+// compiledInfixMap gets the infix map that ApplyGrammar, TryInsert or Optimize compiled from InfixLists. It
+// does not store anything, since the runners are made by concurrent lookups. A dictionary that got its
+// InfixLists some other way (e.g. from JSON) compiles them for every runner until one of those is called.
+func (dictionary *Dictionary) compiledInfixMap() map[string][]infix {
+	if dictionary.InfixLists == nil {
+		return infixMap
+	}
+	if dictionary.infixMap == nil {
+		return compileInfixLists(dictionary.InfixLists)
+	}
+
+	return dictionary.infixMap
+}
+
+// compileInfixMap compiles InfixLists if it has not been done, see compiledInfixMap.
+func (dictionary *Dictionary) compileInfixMap() {
+	if dictionary.InfixLists != nil && dictionary.infixMap == nil {
+		dictionary.infixMap = compileInfixLists(dictionary.InfixLists)
+	}
 }
 
 func (dictionary *Dictionary) Lookup(word string) []Result {
 	return dictionary.Runner().Run(word)
 }
 
+func (dictionary *Dictionary) LookupContext(ctx context.Context, word string) ([]Result, error) {
+	return dictionary.Runner().RunContext(ctx, word)
+}
+
 func (dictionary *Dictionary) Extract(words string) []Result {
 	return dictionary.Runner().Extract(words)
 }
 
+func (dictionary *Dictionary) ExtractContext(ctx context.Context, words string) ([]Result, error) {
+	return dictionary.Runner().ExtractContext(ctx, words)
+}
+
 func (dictionary *Dictionary) Optimize() {
+	dictionary.compileInfixMap()
 	dictionary.Root.Compact()
 	dictionary.Root.SortChildren()
 	dictionary.IsSorted = true
 }
 
+// Insert is like TryInsert, but it panics if the entry could not be inserted.
 func (dictionary *Dictionary) Insert(entry Entry) {
-	root := &dictionary.Root
-	dictionary.IsSorted = false
+	if err := dictionary.TryInsert(entry); err != nil {
+		panic(err)
+	}
+}
+
+// TryInsert generates the trees for the entry and adds them to the dictionary. If it fails, an
+// *EntryError is returned and the dictionary is left as it was, except that SubTreeMap is generated if it
+// was not set, since the phrases are looked up with it.
+func (dictionary *Dictionary) TryInsert(entry Entry) (err error) {
+	for _, flag := range entry.Flags {
+		if !slices.Contains(knownFlags, flag) {
+			return &EntryError{ID: entry.ID, Word: entry.Word, Err: fmt.Errorf("%w: %s", ErrUnknownFlag, flag)}
+		}
+	}
+	if dictionary.SkipArchaic && entry.HasFlag("archaic") {
+		return nil
+	}
 
 	if dictionary.SubTreeMap == nil {
 		dictionary.SubTreeMap = GenerateInitialSubTreeMap()
 	}
-	if dictionary.Phrases == nil {
-		dictionary.Phrases = make(map[string][]Result)
+	dictionary.compileInfixMap()
+
+	// The generators build with CombineTrees and MergedWith, so their failures come as panics.
+	defer func() {
+		if r := recover(); r != nil {
+			recoveredErr, ok := r.(error)
+			if !ok || !errors.Is(recoveredErr, ErrMergeFailed) {
+				panic(r)
+			}
+
+			err = &EntryError{ID: entry.ID, Word: entry.Word, Err: recoveredErr}
+		}
+	}()
+
+	// Everything is staged first so that a failure half-way does not leave half an entry behind.
+	root := EmptyTree()
+	adpositionSuffixes := EmptyTree()
+	adpositionPrefixes := EmptyTree()
+	adpositionPrefixesSg := EmptyTree()
+	var adpositions []string
+	phrases := make(map[string][]Result)
+	numerals := make(map[int]string)
+
+	// The spellings all have the same words, so they all keep the head.
+	headWord := entry.HeadWord
+
+	// Reef entries are already in the dialect's spelling, and the standard ones should not be made from them.
+	spellings := WithAlternativeSpellingVariants(strings.ToLower(entry.WordWithInfixBrackets()))
+	if entry.HasFlag("reef") {
+		spellings = []SpellingVariant{{Text: spellings[0].Text, Spelling: SpellingReef}}
+	}
+
+	// The syllable marks are respelled along with the word, so that the variants get the stress as well. The
+	// variants that change the syllables (e.g. sä- to s-) do not come out the same with the marks, and they're
+	// left without.
+	markedSpellings := make(map[string]string, len(spellings))
+	if len(entry.Syllables) > 0 {
+		for _, marked := range WithAlternativeSpellingVariants(strings.ToLower(entry.wordWithSyllableMarks())) {
+			word, _, _ := parseSyllableMarks(marked.Text)
+			markedSpellings[word] = marked.Text
+		}
 	}
 
-	for _, spelling := range WithAlternativeSpellings(strings.ToLower(entry.WordWithInfixBrackets())) {
+	for _, spelling := range spellings {
 		entry := entry
-		entry.SetWordAndInfixes(spelling)
+		if marked, ok := markedSpellings[spelling.Text]; ok {
+			entry.SetWordAndInfixes(marked)
+		} else {
+			entry.SetWordAndInfixes(spelling.Text)
+		}
+		entry.HeadWord = headWord
 
+		// The results are tagged with the spelling, so each spelling gets its own tree first.
+		spellingRoot := EmptyTree()
 		uninflectables := EmptyTree()
 		uninflectableCount := 0
 
 		for _, pos := range entry.PoS {
 			switch pos {
-			case "adj.", "num.":
-				root.MergeFrom(*AdjectiveFromEntry(entry))
+			case "adj.":
+				spellingRoot.MergeFrom(*AdjectiveFromEntry(entry))
+			case "num.":
+				// Numerals are matched by the numeral tree, which takes the ID from Numerals if listed.
+				if value, ordinal, ok := ParseNumeral(strings.ToLower(entry.Word)); ok && !ordinal {
+					numerals[value] = entry.ID
+					if len(entry.PoS) > 1 {
+						numerals[value] = entry.ID + ":" + pos
+					}
+				} else {
+					spellingRoot.MergeFrom(*AdjectiveFromEntry(entry))
+				}
 			case "n.", "prop.n.":
-				root.MergeFrom(*NounFromEntry(entry))
+				spellingRoot.MergeFrom(*NounFromEntry(entry))
 			case "pn.":
-				root.MergeFrom(*PronounFromEntry(entry))
+				spellingRoot.MergeFrom(*PronounFromEntry(entry))
 			case "vin.", "vim.", "vtr.", "vtrm.":
-				root.MergeFrom(*VerbFromEntry(entry))
+				if entry.InfixPositions == nil && !isSiVerb(entry.Word) {
+					return &EntryError{ID: entry.ID, Word: entry.Word, Err: ErrMissingInfixes}
+				}
+
+				spellingRoot.MergeFrom(*VerbFromEntry(entry))
 			case "inter.":
 				hasFlag := false
 				if entry.HasFlag("inter:adj.") {
-					root.MergeFrom(*AdjectiveFromEntry(entry))
+					spellingRoot.MergeFrom(*AdjectiveFromEntry(entry))
 					hasFlag = true
 				}
 				if entry.HasFlag("inter:n.") {
-					root.MergeFrom(*NounFromEntry(entry))
+					spellingRoot.MergeFrom(*NounFromEntry(entry))
 					hasFlag = true
 				}
 				if entry.HasFlag("inter:adv.") && !entry.HasFlag("inter:n.") {
-					root.MergeFrom(*UninflectableWordFromEntry(entry, ""))
+					spellingRoot.MergeFrom(*UninflectableWordFromEntry(entry, ""))
 					hasFlag = true
 				}
 
 				if !hasFlag {
-					root.MergeFrom(*AffixedOnlyAdjectiveFromEntry(entry))
-					root.MergeFrom(*NounFromEntry(entry))
+					spellingRoot.MergeFrom(*AffixedOnlyAdjectiveFromEntry(entry))
+					spellingRoot.MergeFrom(*NounFromEntry(entry))
 				}
 			case "ph.":
-				runner := dictionary.Runner()
-				runner.PhraseMap = nil
+				entries, err := dictionary.phraseTemplates(entry.Word)
+				if err != nil {
+					return &EntryError{ID: entry.ID, Word: entry.Word, Err: err}
+				}
 
-				entries := runner.ExtractWithoutSkipping(entry.Word)
 				if entries != nil {
-					dictionary.Phrases[entry.ID] = simplestResultSet(entries)
+					phrases[entry.ID] = entries
+				} else if strings.Contains(entry.Word, "{") {
+					return &EntryError{ID: entry.ID, Word: entry.Word, Err: ErrUnknownPhraseWord}
 				} else if entry.InfixPositions != nil {
-					root.MergeFrom(*VerbFromEntry(entry))
+					spellingRoot.MergeFrom(*VerbFromEntry(entry))
 				} else {
 					uninflectables.MergeFrom(*UninflectableWordFromEntry(entry, pos))
 					uninflectableCount++
 				}
 			case "adp.":
 				adposition, suffix := AdpositionFromEntry(entry)
-				root.MergeFrom(*adposition)
-				dictionary.SubTreeMap["nsadp"].MergeFrom(*suffix)
+				spellingRoot.MergeFrom(*adposition)
+				adpositionSuffixes.MergeFrom(*suffix)
+				adpositionPrefixes.MergeFrom(*AdpositionPrefixFromEntry(entry, "np1"))
+				adpositionPrefixesSg.MergeFrom(*AdpositionPrefixFromEntry(entry, "np1_sg"))
+				if word := strings.TrimSuffix(strings.ToLower(entry.Word), "+"); !slices.Contains(adpositions, word) {
+					adpositions = append(adpositions, word)
+				}
 			default:
 				uninflectables.MergeFrom(*UninflectableWordFromEntry(entry, pos))
 				uninflectableCount++
@@ -103,12 +249,84 @@ func (dictionary *Dictionary) Insert(entry Entry) {
 
 		if uninflectableCount != 0 {
 			if uninflectableCount != len(entry.PoS) {
-				root.MergeFrom(*uninflectables)
+				spellingRoot.MergeFrom(*uninflectables)
 			} else {
-				root.MergeFrom(*UninflectableWordFromEntry(entry, ""))
+				spellingRoot.MergeFrom(*UninflectableWordFromEntry(entry, ""))
 			}
 		}
+
+		tagSpelling(spellingRoot, spelling.Spelling)
+		root.MergeFrom(*spellingRoot)
+	}
+
+	if len(entry.Irregulars) > 0 {
+		root.MergeFrom(*IrregularFormsFromEntry(entry))
+	}
+
+	nsadp := dictionary.SubTreeMap["nsadp"]
+	if nsadp == nil {
+		return &EntryError{ID: entry.ID, Word: entry.Word, Err: &SubTreeError{Name: "nsadp", Err: ErrUnknownSubTree}}
+	}
+
+	if _, err := dictionary.Root.TryMergedWith(*root); err != nil {
+		return &EntryError{ID: entry.ID, Word: entry.Word, Err: err}
+	}
+	dictionary.IsSorted = false
+	nsadp.MergeFrom(*adpositionSuffixes)
+	dictionary.mergeIntoSubTree("npadp", adpositionPrefixes)
+	dictionary.mergeIntoSubTree("npadp_sg", adpositionPrefixesSg)
+	for _, adposition := range adpositions {
+		if !slices.Contains(dictionary.Adpositions, adposition) {
+			dictionary.Adpositions = append(dictionary.Adpositions, adposition)
+		}
+	}
+	if len(phrases) > 0 && dictionary.Phrases == nil {
+		dictionary.Phrases = make(map[string][]Result)
+	}
+	for id, phrase := range phrases {
+		dictionary.Phrases[id] = phrase
+	}
+	if len(numerals) > 0 && dictionary.Numerals == nil {
+		dictionary.Numerals = make(map[int]string)
+	}
+	for value, resultValue := range numerals {
+		dictionary.Numerals[value] = resultValue
+	}
+	if dictionary.KeepLemmas {
+		if dictionary.Lemmas == nil {
+			dictionary.Lemmas = make(map[string]Lemma)
+		}
+		dictionary.Lemmas[entry.ID] = Lemma{Word: entry.Word, PoS: entry.PoS, Syllables: entry.Syllables, Stress: entry.Stress}
 	}
+	if len(entry.Irregulars) > 0 {
+		if dictionary.Irregulars == nil {
+			dictionary.Irregulars = make(map[string][]Result)
+		}
+
+		dictionary.Irregulars[entry.ID] = IrregularResultsFromEntry(entry)
+	}
+
+	return nil
+}
+
+// mergeIntoSubTree adds to a subtree that the Dictionary fills. Dictionaries from before the subtree was
+// added to the grammar do not have it, so it's created if needed.
+func (dictionary *Dictionary) mergeIntoSubTree(name string, tree *Node) {
+	if len(tree.Children) == 0 {
+		return
+	}
+
+	if dictionary.SubTreeMap[name] == nil {
+		dictionary.SubTreeMap[name] = EmptyTree()
+	}
+
+	dictionary.SubTreeMap[name].MergeFrom(*tree)
+}
+
+// knownFlags lists the entry flags that Insert understands. See Entry.Flags for what they do.
+var knownFlags = []string{
+	"loanword", "inter:adj.", "inter:n.", "inter:adv.",
+	"noplural", "nocase", "reef", "archaic", "noderiv",
 }
 
 // UninflectableWordFromEntry generates a plain word. It will use the `pos` argument if there are multiple
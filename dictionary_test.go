@@ -3,11 +3,12 @@ package lutral
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
 func miniDict() *Dictionary {
-	dict := &Dictionary{}
+	dict := &Dictionary{KeepLemmas: true}
 	dict.Insert(*ParseEntry("2548:txo:conj."))
 	dict.Insert(*ParseEntry("2224:to:part."))
 	dict.Insert(*ParseEntry("616:irayo:intj.,n."))
@@ -17,6 +18,7 @@ func miniDict() *Dictionary {
 	dict.Insert(*ParseEntry("2080:teri:adp."))
 	dict.Insert(*ParseEntry("1108:mì:adp."))
 	dict.Insert(*ParseEntry("676:ka:adp."))
+	dict.Insert(*ParseEntry("624:fpi+:adp."))
 	dict.Insert(*ParseEntry("-1008:l<0><1><2>ok:vtr.,adp."))
 	dict.Insert(*ParseEntry("4468:kxa:n."))
 	dict.Insert(*ParseEntry("812:k<0><1><2>in:vtr."))
@@ -101,6 +103,8 @@ func miniDict() *Dictionary {
 	dict.Insert(*ParseEntry("13239:txe'lanti wrrzärìp:ph."))
 	dict.Insert(*ParseEntry("10368:tìtseri:n."))
 	dict.Insert(*ParseEntry("11608:to tìtseri:ph."))
+	dict.Insert(*ParseEntry("1176:mune:num."))
+	dict.Insert(*ParseEntry("3496:vol:num."))
 
 	return dict
 }
@@ -145,6 +149,24 @@ func TestDictionary_Lookup(t *testing.T) {
 		{"apeseng", "1520 a-"},
 		{"pesengìl", "1520 -ìl"},
 		{"polpxayìl", ""},
+		{"fpisìfmetok", "2140 fpi- t→s"},
+		{"fpitìfmetok", ""},
+		{"fpiaysìfmetok", "2140 fpi-ay- t→s"},
+		{"mìtìfmetok", "2140 mì-"},
+		{"mìsìfmetok", ""},
+		{"fpisìfmetokìl", ""},
+		{"fpisìfmetokteri", ""},
+		{"fpisìfmetokti", ""},
+		{"fpisìfmetokto", "2140 fpi- -to t→s"},
+		{"fpisìfmetoksì", "2140 fpi- -sì t→s"},
+		{"fpi", "624"},
+		{"mune", "1176 (2)"},
+		{"muve", "1176 (2) -ve"},
+		{"vol", "3496 (8)"},
+		{"amrrvomun", "#:num. (42) a-"},
+		{"zamvolmun", "#:num. (74)"},
+		{"mevolawvea", "#:num. (17) -ve-a"},
+		{"mrrvo", ""},
 	}
 
 	for _, row := range table {
@@ -219,6 +241,96 @@ func TestDictionary_Extract(t *testing.T) {
 	}
 }
 
+func TestDictionary_Extract_Phrases(t *testing.T) {
+	dict := miniDict()
+	dict.Insert(*ParseEntry("-1:eltut heykahaw to tìtseri:ph."))
+	dict.Insert(*ParseEntry("-2:heykahaw ma:ph."))
+	dict.Insert(*ParseEntry("-3:" + strings.Repeat("kifkey ", 39) + "kifkey:ph."))
+
+	table := []struct {
+		Lookup   string
+		Expected string
+	}{
+		{"eltut heykahaw, ma kifkey, to tìtseri", "[1] 13458;[3] 1056;[4] 800;[5] 11608"},
+		{"eltut heykahaw to tìtseri", "[1] -1"},
+		{"eltut heykahaw ma", "[1] 13458;[3] 1056"},
+		{"kifkey heykahaw ma", "[1] 800;[2] -2"},
+		{"to tìtseri to tìtseri", "[1] 11608;[3] 11608"},
+		{strings.Repeat("kifkey ", 40), "[1] -3"},
+		{strings.Repeat("kifkey ", 41), "[1] -3;[41] 800"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Lookup, func(t *testing.T) {
+			first := dict.Extract(row.Lookup)
+
+			resStr := ""
+			for _, res := range first {
+				if len(resStr) != 0 {
+					resStr += ";"
+				}
+				resStr += res.String()
+			}
+			assert.Equal(t, row.Expected, resStr)
+
+			// The phrases are in a map, so make sure its order does not matter.
+			for i := 0; i < 20; i++ {
+				assert.Equal(t, first, dict.Extract(row.Lookup))
+			}
+		})
+	}
+}
+
+func TestDictionary_Extract_PhraseSlots(t *testing.T) {
+	dict := miniDict()
+	dict.Insert(*ParseEntry("-1:{n|pn/patientive} k<0><1>am<2>e:ph."))
+	dict.Insert(*ParseEntry("-2:ma {any}:ph."))
+	dict.Insert(*ParseEntry("-3:kaltxì {gap} kifkey:ph."))
+
+	table := []struct {
+		Lookup   string
+		Expected string
+	}{
+		{"kifkeyti kolame", "[1] -1 <ol> {800 -ti}"},
+		{"tsawl oeti kame", "[1] 5268 -l;[2] -1 {1380 -ti}"},
+		{"kifkey kame", "[1] 800;[2] 700"},
+		{"ma kifkey", "[1] -2 {800}"},
+		{"ma blorg", "[1] -2 {blorg}"},
+		{"kaltxì kifkey", "[1] -3"},
+		{"kaltxì, oe kifkey", "[1] -3;[2] 1380"},
+		{"kaltxì kaltxì kifkey kifkey", "[1] -3;[2] 692;[4] 800"},
+		{"kaltxì. oe lu eltu. tsawl kifkey", "[1] 692;[2] 1380;[4] 264;[5] 5268 -l;[6] 800"},
+		{"kaltxì. kifkey", "[1] 692;[2] 800"},
+		{"kaltxì oe oe oe oe oe oe kifkey", "[1] -3;[2] 1380;[3] 1380;[4] 1380;[5] 1380;[6] 1380;[7] 1380"},
+		{"kaltxì oe oe oe oe oe oe oe kifkey", "[1] 692;[2] 1380;[3] 1380;[4] 1380;[5] 1380;[6] 1380;[7] 1380;[8] 1380;[9] 800"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Lookup, func(t *testing.T) {
+			resStr := ""
+			for _, res := range dict.Extract(row.Lookup) {
+				if len(resStr) != 0 {
+					resStr += ";"
+				}
+				resStr += res.String()
+
+				for _, slot := range res.Slots {
+					if slot.ID == "" {
+						resStr += " {" + slot.Form + "}"
+					} else {
+						slot.Position = 0
+						resStr += " {" + slot.String() + "}"
+					}
+				}
+			}
+			assert.Equal(t, row.Expected, resStr)
+		})
+	}
+
+	err := dict.TryInsert(*ParseEntry("-4:{n} blorg:ph."))
+	assert.ErrorIs(t, err, ErrUnknownPhraseWord)
+}
+
 func BenchmarkDictionary_Example(b *testing.B) {
 	dict := miniDict()
 
@@ -234,3 +346,230 @@ func BenchmarkDictionary_Example(b *testing.B) {
 		})
 	}
 }
+
+func TestDictionary_TryInsert(t *testing.T) {
+	dict := miniDict()
+	dict.Optimize()
+	size := dict.Root.Size()
+	lemmaCount := len(dict.Lemmas)
+
+	err := dict.TryInsert(*ParseEntry("3356:tsun:vim."))
+	assert.ErrorIs(t, err, ErrMissingInfixes)
+	assert.EqualError(t, err, "entry 3356 (tsun): verb has no infix positions")
+	assert.Equal(t, size, dict.Root.Size())
+	assert.Equal(t, lemmaCount, len(dict.Lemmas))
+	assert.True(t, dict.IsSorted)
+
+	assert.Panics(t, func() {
+		dict.Insert(*ParseEntry("3356:tsun:vim."))
+	})
+
+	dict.SubTreeMap["np"] = BuildTree("$np_missing")
+	err = dict.TryInsert(*ParseEntry("-2:ikran ke tsun:ph."))
+	assert.ErrorIs(t, err, ErrUnknownSubTree)
+	assert.EqualError(t, err, "entry -2 (ikran ke tsun): subtree $np_missing: unknown subtree")
+
+	assert.NoError(t, dict.TryInsert(*ParseEntry("-3:tsaheylu:n.")))
+
+	size = dict.Root.Size()
+	err = dict.TryInsert(*ParseEntry("-4:tsaheylu:n.:plural"))
+	assert.ErrorIs(t, err, ErrUnknownFlag)
+	assert.EqualError(t, err, "entry -4 (tsaheylu): unknown flag: plural")
+	assert.Equal(t, size, dict.Root.Size())
+}
+
+func TestDictionary_Insert_Stress(t *testing.T) {
+	dict := &Dictionary{}
+	dict.Insert(*ParseEntry("-1:ˈtx<0><1>rr.f<2>en:vtr."))
+	dict.Insert(*ParseEntry("-2:px<0><1>ll.ˈtx<2>e:vtr."))
+	dict.Insert(*ParseEntry("-3:ˈkìn.tì:adj.:loanword"))
+	dict.Insert(*ParseEntry("-4:pän.ˈtì:adj.:loanword"))
+
+	table := []struct {
+		Lookup   string
+		Expected string
+	}{
+		{"txerfen", ""},
+		{"derfen", ""}, // The Reef spelling keeps the stress.
+		{"poltxe", "-2 <ol> px→p"},
+		{"bolde", "-2 <ol>"},
+		{"kìnta", "-3 -a"},
+		{"kìntìa", "-3 -a"},
+		{"pänta", ""},
+		{"päntìa", "-4 -a"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Lookup, func(t *testing.T) {
+			resStr := ""
+			for _, res := range dict.Lookup(row.Lookup) {
+				if len(resStr) != 0 {
+					resStr += ";"
+				}
+				resStr += res.String()
+			}
+			assert.Equal(t, row.Expected, resStr)
+		})
+	}
+}
+
+func TestDictionary_Insert_KeepLemmas(t *testing.T) {
+	dict := &Dictionary{}
+	dict.Insert(*ParseEntry("-1:ha.ˈme:n."))
+	assert.Nil(t, dict.Lemmas)
+
+	dict.KeepLemmas = true
+	dict.Insert(*ParseEntry("-2:ˈtsì.ha:n."))
+	assert.Equal(t, map[string]Lemma{
+		"-2": {Word: "tsìha", PoS: []string{"n."}, Syllables: []int{0, 4}, Stress: intPtr(0)},
+	}, dict.Lemmas)
+}
+
+func TestDictionary_Insert_Flags(t *testing.T) {
+	dict := Dictionary{SkipArchaic: true}
+	dict.Insert(*ParseEntry("1108:mì+:adp."))
+	dict.Insert(*ParseEntry("-1:ikran:n.:noplural"))
+	dict.Insert(*ParseEntry("-2:tìfmetok:n.:nocase"))
+	dict.Insert(*ParseEntry("-3:tìsyìmawnun'i:n.:reef"))
+	dict.Insert(*ParseEntry("-4:fm<0><1>et<2>ok:vtr.:noderiv"))
+	dict.Insert(*ParseEntry("-5:uvan s<0><1><2>i:vin.:noderiv"))
+	dict.Insert(*ParseEntry("-6:kelku:n.:noderiv"))
+	dict.Insert(*ParseEntry("-7:ikranay:n.:archaic"))
+
+	table := []struct {
+		Lookup   string
+		Expected string
+	}{
+		{"ikranìl", "-1 -ìl"},
+		{"fìikran", "-1 fì-"},
+		{"ayikran", ""},
+		{"meikran", ""},
+		{"fayikran", ""},
+		{"mìfneikran", "-1 mì-fne-"},
+		{"mìayikran", ""},
+		{"aysìfmetok", "-2 ay- t→s"},
+		{"tìfmetoksì", "-2 -sì"},
+		{"tìfmetokìl", ""},
+		{"tìsyìmawnun'i", "-3"},
+		{"tsyìmawnun'i", ""},
+		{"chìmawnun'i", ""},
+		{"fmetok", "-4"},
+		{"fmetokyu", ""},
+		{"fmusetok", ""},
+		{"uvan si", "-5"},
+		{"uvansiyu", ""},
+		{"kelkunga'", ""},
+		{"kelkuti", "-6 -ti"},
+		{"ikranay", ""},
+	}
+
+	for _, row := range table {
+		t.Run(row.Lookup, func(t *testing.T) {
+			resStr := ""
+			for _, res := range dict.Lookup(row.Lookup) {
+				if len(resStr) != 0 {
+					resStr += ";"
+				}
+				resStr += res.String()
+			}
+
+			assert.Equal(t, row.Expected, resStr)
+		})
+	}
+
+	t.Run("reef with StrictForest", func(t *testing.T) {
+		res := dict.Lookup("tìsyìmawnun'i")
+		if assert.Len(t, res, 1) {
+			assert.Equal(t, SpellingReef, res[0].Spelling)
+		}
+
+		dict.StrictForest = true
+		defer func() { dict.StrictForest = false }()
+		assert.Empty(t, dict.Lookup("tìsyìmawnun'i"))
+	})
+}
+
+func TestDictionary_Lookup_Spellings(t *testing.T) {
+	dict := Dictionary{}
+	dict.Insert(*ParseEntry("-1:kxanì:adj."))
+	dict.Insert(*ParseEntry("-2:tìsyìmawnun'i:n."))
+	dict.Insert(*ParseEntry("-3:futa:conj."))
+	dict.Insert(*ParseEntry("-4:fngä'än:n."))
+	dict.Insert(*ParseEntry("-5:bumbe:n.:reef")) // Fake Reef word
+
+	table := []struct {
+		Lookup   string
+		Expected string
+		Spelling Spelling
+		Strict   bool
+	}{
+		{"kxanì", "-1", SpellingStandard, true},
+		{"ganì", "-1", SpellingReef, false},
+		{"aganì", "-1 a-", SpellingReef, false},
+		{"tìsyìmawnun'iti", "-2 -ti", SpellingStandard, true},
+		{"tsyìmawnun'iti", "-2 -ti", SpellingCluster, true},
+		{"tìshìmawnun'iti", "-2 -ti", SpellingReef, false},
+		{"chìmawnun'iti", "-2 -ti", SpellingReefCluster, false},
+		{"futa", "-3", SpellingStandard, true},
+		{"fta", "-3", SpellingContraction, true},
+		{"fngään", "-4", SpellingReef, false},
+		{"bumbe", "-5", SpellingReef, false},
+		{"bumbeti", "-5 -ti", SpellingReef, false},
+	}
+
+	for _, row := range table {
+		t.Run(row.Lookup, func(t *testing.T) {
+			res := dict.Lookup(row.Lookup)
+			if assert.Len(t, res, 1) {
+				assert.Equal(t, row.Expected, res[0].String())
+				assert.Equal(t, row.Spelling, res[0].Spelling)
+			}
+
+			dict.StrictForest = true
+			defer func() { dict.StrictForest = false }()
+			assert.Equal(t, row.Strict, len(dict.Lookup(row.Lookup)) > 0)
+		})
+	}
+}
+
+func TestDictionary_Lookup_Derivations(t *testing.T) {
+	dict := miniDict()
+	dict.Insert(*ParseEntry("-1:mrrvolawve:num."))
+	dict.Insert(*ParseEntry("-2:peu:inter.:inter:adj.")) // Fake interrogative
+
+	table := []struct {
+		Lookup     string
+		PoS        string
+		BasePoS    string
+		Derivation string
+	}{
+		{"tìfmusetok", "n.", "vtr.", DerivationGerund},
+		{"fmetokyu", "n.", "vtr.", DerivationAgent},
+		{"fmetoktswo", "n.", "vtr.", DerivationAbility},
+		{"säfmetok", "n.", "vtr.", DerivationInstrument},
+		{"fmusetok", "adj.", "vtr.", DerivationActiveParticiple},
+		{"fmawnetok", "adj.", "vtr.", DerivationPassiveParticiple},
+		{"tsukfmetok", "adj.", "vtr.", DerivationTsukAdjective},
+		{"'asap-susi", "adj.", "vin.", DerivationActiveParticiple},
+		{"'asap-seykawni", "adj.", "vin.", DerivationPassiveParticiple},
+		{"eltunga'", "adj.", "n.", DerivationNgaAdjective},
+		{"leeltu", "adj.", "n.", DerivationLeAdjective},
+		{"nìfe'lup", "adv.", "adj.", DerivationAdverb},
+		{"nìmrrvolawve", "adv.", "num.", DerivationAdverb},
+		{"nìpeu", "adv.", "inter.", DerivationAdverb},
+		{"fmetok", "", "", ""},
+	}
+
+	for _, row := range table {
+		t.Run(row.Lookup, func(t *testing.T) {
+			res := dict.Lookup(row.Lookup)
+			if !assert.Len(t, res, 1) {
+				return
+			}
+
+			assert.Equal(t, row.PoS, res[0].PoS)
+			assert.Equal(t, row.BasePoS, res[0].BasePoS)
+			assert.Equal(t, row.Derivation, res[0].Derivation)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package lutral
+
+import (
+	"context"
+	"slices"
+)
+
+// Reading is the result at a position in the text that fits the sentence best, along with the other results
+// found there.
+type Reading struct {
+	Position     int      `json:"position"`
+	Best         Result   `json:"best"`
+	Alternatives []Result `json:"alternatives,omitempty"`
+}
+
+// contextCost is taken off the cost of a result that fits with the rest of the sentence, and added to one that
+// does not. It's more than an affix costs with NewDefaultScorer, so the context wins over the affix count.
+// Some checks only add it, since fitting them is nothing more than what is expected.
+const contextCost = 2
+
+// Disambiguate is like Extract, but it picks the reading at each position that fits the sentence best. The
+// results are ranked by Scorer (NewDefaultScorer if not set) and then by how they fit with the results next
+// to them:
+//
+//   - an adjective with the attributive a- or -a should have a noun on that side,
+//   - an agentive or patientive noun should have a transitive verb in the sentence, and the other way around,
+//   - an adposition suffix should be on a noun or pronoun.
+//
+// The results are checked against the top-ranked results of the other words. The text is split into
+// sentences like in WriteCoNLLUDocument, but the positions count from the start of the text. The words that
+// are not found have a Best without an ID.
+func (runner *Runner) Disambiguate(text string) []Reading {
+	res, err := runner.DisambiguateContext(context.Background(), text)
+	panicOnTreeError(err)
+
+	return res
+}
+
+// DisambiguateContext is like Disambiguate, but with the same limits as ExtractContext.
+func (runner *Runner) DisambiguateContext(ctx context.Context, text string) ([]Reading, error) {
+	scorer := runner.Scorer
+	if scorer == nil {
+		scorer = NewDefaultScorer()
+	}
+
+	var readings []Reading
+	position := 0
+	for _, sentence := range splitSentences(text) {
+		keepUnknown := runner.keepUnknown
+		runner.keepUnknown = true
+		results, err := runner.extract(ctx, sentence, false)
+		runner.keepUnknown = keepUnknown
+		if len(results) > 0 {
+			readings = append(readings, runner.disambiguateSentence(results, position, scorer)...)
+		}
+		position += runner.wordCount
+		if err != nil {
+			return readings, err
+		}
+	}
+
+	return readings, nil
+}
+
+// disambiguateSentence picks the readings for the results of one sentence, with the positions moved past
+// the earlier sentences.
+func (runner *Runner) disambiguateSentence(results []Result, position int, scorer Scorer) []Reading {
+	groups := make([][]Result, 0, results[len(results)-1].Position)
+	start := 0
+	for i := 1; i <= len(results); i++ {
+		if i == len(results) || results[i].Position != results[start].Position {
+			group := Rank(results[start:i], scorer)
+			for j := range group {
+				group[j].Position += position
+			}
+
+			groups = append(groups, group)
+			start = i
+		}
+	}
+
+	best := runner.bestPath(groups)
+
+	readings := make([]Reading, 0, len(groups))
+	for i, group := range groups {
+		alternatives := make([]Result, 0, len(group)-1)
+		alternatives = append(alternatives, group[:best[i]]...)
+		alternatives = append(alternatives, group[best[i]+1:]...)
+		if len(alternatives) == 0 {
+			alternatives = nil
+		}
+
+		readings = append(readings, Reading{
+			Position:     group[0].Position,
+			Best:         group[best[i]],
+			Alternatives: alternatives,
+		})
+	}
+
+	return readings
+}
+
+// bestPath finds the index of the best result in each group, where the cost of a path is the scores of the
+// results plus their context costs.
+func (runner *Runner) bestPath(groups [][]Result) []int {
+	// costs[i][j] is the lowest cost of a path ending with groups[i][j], and prev[i][j] is where it came from.
+	costs := make([][]float64, len(groups))
+	prev := make([][]int, len(groups))
+	for i, group := range groups {
+		costs[i] = make([]float64, len(group))
+		prev[i] = make([]int, len(group))
+
+		for j := range group {
+			cost := group[j].Score + runner.sentenceCost(groups, i, j)
+			if i == 0 {
+				costs[i][j] = cost + runner.attributiveCost(nil, &group[j])
+				continue
+			}
+
+			for k := range groups[i-1] {
+				pathCost := costs[i-1][k] + cost + runner.attributiveCost(&groups[i-1][k], &group[j])
+				if k == 0 || pathCost < costs[i][j] {
+					costs[i][j] = pathCost
+					prev[i][j] = k
+				}
+			}
+		}
+	}
+
+	last := len(groups) - 1
+	best := make([]int, len(groups))
+	for j := range groups[last] {
+		costs[last][j] += runner.attributiveCost(&groups[last][j], nil)
+		if costs[last][j] < costs[last][best[last]] {
+			best[last] = j
+		}
+	}
+	for i := last; i > 0; i-- {
+		best[i-1] = prev[i][best[i]]
+	}
+
+	return best
+}
+
+// attributiveCost checks the attributive a between two results next to each other, where nil is the start
+// or end of the sentence. An adjective with -a needs a noun after it, and one with a- needs a noun before it.
+func (runner *Runner) attributiveCost(left, right *Result) float64 {
+	cost := 0.0
+	if left != nil && slices.Contains(left.Suffixes, "a") && runner.hasPoS(*left, "adj.") {
+		cost += fitCost(right != nil && runner.hasPoS(*right, "n.", "pn."))
+	}
+	if right != nil && slices.Contains(right.Prefixes, "a") && runner.hasPoS(*right, "adj.") {
+		cost += fitCost(left != nil && runner.hasPoS(*left, "n.", "pn."))
+	}
+
+	return cost
+}
+
+// sentenceCost checks groups[i][j] against the other words in the sentence.
+func (runner *Runner) sentenceCost(groups [][]Result, i, j int) float64 {
+	result := groups[i][j]
+	cost := 0.0
+
+	if slices.ContainsFunc(result.Suffixes, func(suffix string) bool { return slices.Contains(runner.Adpositions, suffix) }) {
+		if !runner.hasPoS(result, "n.", "pn.") {
+			cost += contextCost
+		}
+	}
+
+	if resultCase := result.Features().Case; resultCase == CaseAgentive || resultCase == CasePatientive {
+		cost += fitCost(runner.anyOtherResult(groups, i, func(other Result) bool {
+			return runner.hasPoS(other, "vtr.", "vtrm.")
+		}))
+	} else if runner.hasPoS(result, "vtr.", "vtrm.") && runner.anyOtherResult(groups, i, func(other Result) bool {
+		otherCase := other.Features().Case
+		return otherCase == CaseAgentive || otherCase == CasePatientive
+	}) {
+		cost -= contextCost
+	}
+
+	return cost
+}
+
+// anyOtherResult checks if the top-ranked result of any group other than groups[i] matches.
+func (runner *Runner) anyOtherResult(groups [][]Result, i int, match func(result Result) bool) bool {
+	for k, group := range groups {
+		if k != i && match(group[0]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fitCost(fits bool) float64 {
+	if fits {
+		return -contextCost
+	}
+
+	return contextCost
+}
+
+func (dictionary *Dictionary) Disambiguate(text string) []Reading {
+	return dictionary.Runner().Disambiguate(text)
+}
+
+func (dictionary *Dictionary) DisambiguateContext(ctx context.Context, text string) ([]Reading, error) {
+	return dictionary.Runner().DisambiguateContext(ctx, text)
+}
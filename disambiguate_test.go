@@ -0,0 +1,71 @@
+package lutral
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDictionary_Disambiguate(t *testing.T) {
+	dict := miniDict()
+	dict.Insert(*ParseEntry("-1:keruseya:n."))
+	dict.Insert(*ParseEntry("-2:tsawl:n."))
+	dict.Insert(*ParseEntry("-3:uvanmì:adv."))
+	dict.Insert(*ParseEntry("-4:mì:adp."))
+
+	table := []struct {
+		Text     string
+		Expected string
+	}{
+		{"", ""},
+		{"kaltxì, ma kifkey!", "[1] 692;[2] 1056;[3] 800"},
+		{"keruseya", "[1] -1 (+1)"},
+		{"keruseya kifkey", "[1] 780 -a (+1);[2] 800"},
+		{"kifkey keruseya", "[1] 800;[2] -1 (+1)"},
+		{"tsawl", "[1] -2 (+1)"},
+		{"tsawl kifkeyti tse'a", "[1] 5268 -l (+1);[2] 800 -ti;[3] 2376"},
+		{"kifkeyti tsawl tse'a", "[1] 800 -ti;[2] 5268 -l (+1);[3] 2376"},
+		{"uvanmì", "[1] -3 (+1)"},
+		{"blorg uvanmì", "[1] ;[2] -3 (+1)"},
+		{"tsawl. oe tse'a kifkeyti", "[1] -2 (+1);[2] 1380;[3] 2376;[4] 800 -ti"},
+		{"tsawl tse'a. kifkeyti", "[1] 5268 -l (+1);[2] 2376;[3] 800 -ti"},
+		{"to tìtseri. kaltxì ma kifkey. oe", "[1] 11608;[3] 692;[4] 1056;[5] 800;[6] 1380"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Text, func(t *testing.T) {
+			resStr := ""
+			for _, reading := range dict.Disambiguate(row.Text) {
+				if len(resStr) != 0 {
+					resStr += ";"
+				}
+				resStr += reading.Best.String()
+				if len(reading.Alternatives) > 0 {
+					resStr += fmt.Sprintf(" (+%d)", len(reading.Alternatives))
+				}
+
+				assert.Equal(t, reading.Position, reading.Best.Position)
+			}
+			assert.Equal(t, row.Expected, resStr)
+		})
+	}
+}
+
+func TestDictionary_Disambiguate_Alternatives(t *testing.T) {
+	dict := miniDict()
+	dict.Insert(*ParseEntry("-1:keruseya:n."))
+
+	readings := dict.Disambiguate("keruseya kifkey")
+	if assert.Len(t, readings, 2) {
+		assert.Equal(t, "780", readings[0].Best.ID)
+		assert.Equal(t, 1.0, readings[0].Best.Score)
+		assert.Equal(t, []Result{{ID: "-1", Position: 1, Form: "keruseya", Spelling: SpellingStandard}}, readings[0].Alternatives)
+		assert.Nil(t, readings[1].Alternatives)
+
+		data, err := json.Marshal(readings[1])
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"position": 2, "best": {"id": "800", "index": 2, "form": "kifkey", "spelling": "standard"}}`, string(data))
+	}
+}
@@ -1,6 +1,10 @@
 package lutral
 
-import "strings"
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
 
 // Entry is the minimum information needed to build a tree for the word.
 type Entry struct {
@@ -8,7 +12,23 @@ type Entry struct {
 	Word           string
 	PoS            []string
 	InfixPositions *[2]int
-	// Supported Flags: "loanword"
+	// Syllables holds the byte offsets in Word where each syllable starts, if known.
+	Syllables []int
+	// Stress is the index in Syllables of the stressed syllable, if known.
+	Stress *int
+	// HeadWord is the index of the word that takes the affixes in a multi-word noun, if marked.
+	HeadWord *int
+	// Irregulars are forms that replace the regular ones, given as "irr:" flags.
+	Irregulars []IrregularForm
+	// Supported Flags:
+	//  - "loanword": the final ì is dropped before case endings and the attributive -a, unless it's stressed.
+	//  - "inter:adj.", "inter:n.", "inter:adv.": what an interrogative inflects as.
+	//  - "noplural": the noun does not take the plural prefixes.
+	//  - "nocase": the noun does not take case endings or other suffixes than -sì.
+	//  - "reef": the word is in Reef spelling, so no alternative spellings are made from it, and it is left
+	//    out with StrictForest.
+	//  - "archaic": the word is left out if Dictionary.SkipArchaic is set.
+	//  - "noderiv": no nouns or adjectives are derived from the word (participles, -yu, -nga', etc.).
 	Flags []string
 }
 
@@ -22,8 +42,50 @@ func (e *Entry) WordWithInfixBrackets() string {
 	return res
 }
 
+// wordWithSyllableMarks is like WordWithInfixBrackets, but with the syllable marks that SetWordAndInfixes
+// takes as well.
+func (e *Entry) wordWithSyllableMarks() string {
+	if len(e.Syllables) == 0 {
+		return e.WordWithInfixBrackets()
+	}
+
+	sb := strings.Builder{}
+	for offset := 0; offset <= len(e.Word); offset++ {
+		if i := slices.Index(e.Syllables, offset); i != -1 {
+			if e.Stress != nil && *e.Stress == i {
+				sb.WriteString("ˈ")
+			} else if offset > 0 && e.Word[offset-1] != ' ' {
+				sb.WriteByte('.')
+			}
+		}
+		if e.InfixPositions != nil && e.InfixPositions[0] == offset {
+			sb.WriteString("<0><1>")
+		}
+		if e.InfixPositions != nil && e.InfixPositions[1] == offset {
+			sb.WriteString("<2>")
+		}
+		if offset < len(e.Word) {
+			sb.WriteByte(e.Word[offset])
+		}
+	}
+
+	return sb.String()
+}
+
+// SetWordAndInfixes sets the word from a string with infix brackets (e.g. "t<0><1>ar<2>on"). It also takes
+// syllable marks, where "." separates syllables and "ˈ" goes before the stressed one (e.g. "ˈt<0><1>a.r<2>on"),
+// and a "*" after the head of a multi-word noun (e.g. "txawnulsrung* a yur").
 func (e *Entry) SetWordAndInfixes(wordWithBrackets string) {
+	wordWithBrackets, headWord := parseHeadMark(wordWithBrackets)
+	wordWithBrackets, syllables, stress := parseSyllableMarks(wordWithBrackets)
+	for i, offset := range syllables {
+		syllables[i] = len(infixBracketReplacer.Replace(wordWithBrackets[:offset]))
+	}
+
 	e.Word = wordWithBrackets
+	e.Syllables = syllables
+	e.Stress = stress
+	e.HeadWord = headWord
 
 	if infix0Pos := strings.Index(wordWithBrackets, "<0><1>"); infix0Pos >= 0 {
 		e.InfixPositions = &[2]int{
@@ -35,6 +97,23 @@ func (e *Entry) SetWordAndInfixes(wordWithBrackets string) {
 	}
 }
 
+// IsStressedAt checks whether the syllable that the byte offset in Word is part of is stressed. If
+// the entry does not have that information, known is false.
+func (e *Entry) IsStressedAt(offset int) (stressed bool, known bool) {
+	if e.Stress == nil || len(e.Syllables) == 0 {
+		return false, false
+	}
+
+	syllable := 0
+	for i, start := range e.Syllables {
+		if start <= offset {
+			syllable = i
+		}
+	}
+
+	return syllable == *e.Stress, true
+}
+
 func (e *Entry) HasFlag(pred string) bool {
 	for _, flag := range e.Flags {
 		if pred == flag {
@@ -70,10 +149,96 @@ func ParseEntry(s string) *Entry {
 	}
 
 	if len(split) >= 4 && split[3] != "" {
-		res.Flags = strings.Split(split[3], ",")
+		for _, flag := range strings.Split(split[3], ",") {
+			if irregular, ok := strings.CutPrefix(flag, "irr:"); ok {
+				irregularForm, ok := parseIrregularForm(irregular)
+				if !ok {
+					return nil
+				}
+
+				res.Irregulars = append(res.Irregulars, irregularForm)
+			} else {
+				res.Flags = append(res.Flags, flag)
+			}
+		}
 	}
 
 	return res
 }
 
+// parseHeadMark removes the "*" and returns the index of the word it was on. It is only a mark if there is
+// one, and it ends a word, otherwise the text is left as it is.
+func parseHeadMark(marked string) (word string, headWord *int) {
+	index := strings.Index(marked, "*")
+	if index <= 0 || strings.Count(marked, "*") > 1 || marked[index-1] == ' ' {
+		return marked, nil
+	}
+	if index+1 < len(marked) && marked[index+1] != ' ' {
+		return marked, nil
+	}
+
+	head := strings.Count(marked[:index], " ")
+	return strings.ReplaceAll(marked, "*", ""), &head
+}
+
+// parseSyllableMarks removes the syllable marks, and returns the offsets in the remaining string where
+// each syllable starts. A space also starts a new syllable, but only if there are marks. The text is left
+// as it is if the marks would make an empty syllable (e.g. "abbr." or "e.g."), see validSyllableMarks.
+func parseSyllableMarks(marked string) (word string, syllables []int, stress *int) {
+	if !strings.ContainsAny(marked, ".ˈ") || !validSyllableMarks(marked) {
+		return marked, nil, nil
+	}
+
+	sb := strings.Builder{}
+	syllables = []int{0}
+	startSyllable := func() {
+		if syllables[len(syllables)-1] != sb.Len() {
+			syllables = append(syllables, sb.Len())
+		}
+	}
+
+	for _, ch := range marked {
+		switch ch {
+		case '.':
+			startSyllable()
+		case 'ˈ':
+			startSyllable()
+			stressed := len(syllables) - 1
+			stress = &stressed
+		case ' ':
+			sb.WriteRune(ch)
+			startSyllable()
+		default:
+			sb.WriteRune(ch)
+		}
+	}
+
+	return sb.String(), syllables, stress
+}
+
+// validSyllableMarks checks that every mark is followed by a letter of the syllable it starts, and that a
+// "." comes after the letter of another syllable in the same word. The stress mark may follow a "." or
+// start a word.
+func validSyllableMarks(marked string) bool {
+	prev := ' '
+	for i, ch := range marked {
+		if ch != '.' && ch != 'ˈ' {
+			prev = ch
+			continue
+		}
+
+		next, _ := utf8.DecodeRuneInString(marked[i+len(string(ch)):])
+		if next == utf8.RuneError || next == ' ' || next == '.' || (next == 'ˈ' && ch == 'ˈ') {
+			return false
+		}
+		if ch == '.' && (prev == ' ' || prev == '.') {
+			return false
+		}
+
+		prev = ch
+	}
+
+	return true
+}
+
 var infixBracketReplacer = strings.NewReplacer("<0>", "", "<1>", "", "<2>", "")
@@ -14,6 +14,24 @@ func TestParseEntry(t *testing.T) {
 		{"392:fm<0><1>et<2>ok:vtr.", &Entry{ID: "392", Word: "fmetok", PoS: []string{"vtr."}, InfixPositions: &[2]int{2, 4}}},
 		{"2232:t<0><1><2>ok:vtr.", &Entry{ID: "2232", Word: "tok", PoS: []string{"vtr."}, InfixPositions: &[2]int{1, 1}}},
 		{"11720:kelnì:prop.n.:loanword", &Entry{ID: "11720", Word: "kelnì", PoS: []string{"prop.n."}, InfixPositions: nil, Flags: []string{"loanword"}}},
+		{"464:ˈf<0><1>rr.f<2>en:vtr.", &Entry{ID: "464", Word: "frrfen", PoS: []string{"vtr."}, InfixPositions: &[2]int{1, 4}, Syllables: []int{0, 3}, Stress: intPtr(0)}},
+		{"-1:ha.me.ˈtsì:n.", &Entry{ID: "-1", Word: "hametsì", PoS: []string{"n."}, Syllables: []int{0, 2, 4}, Stress: intPtr(2)}},
+		{"-2:ˈtxawn.ulsrung a tswa.yon:n.", &Entry{ID: "-2", Word: "txawnulsrung a tswayon", PoS: []string{"n."}, Syllables: []int{0, 5, 13, 15, 19}, Stress: intPtr(0)}},
+		{"13491:txawnulsrung* a yur:n.", &Entry{ID: "13491", Word: "txawnulsrung a yur", PoS: []string{"n."}, HeadWord: intPtr(0)}},
+		{"-3:tsko swi.ˈzaw*:n.", &Entry{ID: "-3", Word: "tsko swizaw", PoS: []string{"n."}, Syllables: []int{0, 5, 8}, Stress: intPtr(2), HeadWord: intPtr(1)}},
+		{"-4:abbr.:n.", &Entry{ID: "-4", Word: "abbr.", PoS: []string{"n."}}},
+		{"-5:e.g.:adv.", &Entry{ID: "-5", Word: "e.g.", PoS: []string{"adv."}}},
+		{"-6:ha..me:n.", &Entry{ID: "-6", Word: "ha..me", PoS: []string{"n."}}},
+		{"-7:ha. me:n.", &Entry{ID: "-7", Word: "ha. me", PoS: []string{"n."}}},
+		{"-8:haˈ.me:n.", &Entry{ID: "-8", Word: "haˈ.me", PoS: []string{"n."}}},
+		{"-9:3*4:num.", &Entry{ID: "-9", Word: "3*4", PoS: []string{"num."}}},
+		{"-10:tsko* swizaw*:n.", &Entry{ID: "-10", Word: "tsko* swizaw*", PoS: []string{"n."}}},
+		{"-11:tsko *swizaw:n.", &Entry{ID: "-11", Word: "tsko *swizaw", PoS: []string{"n."}}},
+		{"1380:oe:pn.:irr:oeyä=-yä", &Entry{ID: "1380", Word: "oe", PoS: []string{"pn."}, Irregulars: []IrregularForm{{Form: "oeyä", Affixes: []string{"-yä"}}}}},
+		{"-1:ikran:n.:loanword,irr:ayikranyä=ay+ -ä", &Entry{ID: "-1", Word: "ikran", PoS: []string{"n."}, Flags: []string{"loanword"}, Irregulars: []IrregularForm{{Form: "ayikranyä", Affixes: []string{"ay+", "-ä"}}}}},
+		{"-2:tsmuk:intj.,n.:irr:n.:tsmukyä=-ä", &Entry{ID: "-2", Word: "tsmuk", PoS: []string{"intj.", "n."}, Irregulars: []IrregularForm{{Form: "tsmukyä", PoS: "n.", Affixes: []string{"-ä"}}}}},
+		{"1380:oe:pn.:irr:oeyä", nil},
+		{"1380:oe:pn.:irr::oeyä=-yä", nil},
 		{"2232:t<0><1><2>ok", nil},
 		{"2232:t<0><1><2>ok:", nil},
 		{"2232::vtr.", nil},
@@ -26,3 +44,33 @@ func TestParseEntry(t *testing.T) {
 		})
 	}
 }
+
+func TestEntry_IsStressedAt(t *testing.T) {
+	entry := ParseEntry("464:ˈf<0><1>rr.f<2>en:vtr.")
+	stressed, known := entry.IsStressedAt(1)
+	assert.True(t, stressed)
+	assert.True(t, known)
+
+	stressed, known = entry.IsStressedAt(4)
+	assert.False(t, stressed)
+	assert.True(t, known)
+
+	entry = ParseEntry("464:f<0><1>rrf<2>en:vtr.")
+	stressed, known = entry.IsStressedAt(1)
+	assert.False(t, stressed)
+	assert.False(t, known)
+}
+
+func TestEntry_wordWithSyllableMarks(t *testing.T) {
+	for _, word := range []string{"tìfmetok", "fm<0><1>et<2>ok", "ˈf<0><1>rr.f<2>en", "ha.meˈtsì", "ˈtxawn.ulsrung a tswa.yon", "t<0><1>aˈr<2>on"} {
+		t.Run(word, func(t *testing.T) {
+			entry := Entry{}
+			entry.SetWordAndInfixes(word)
+			assert.Equal(t, word, entry.wordWithSyllableMarks())
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,101 @@
+package lutral
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrStepLimitExceeded is the cause of a LimitError when the lookup went past Runner.MaxStepCount.
+	ErrStepLimitExceeded = errors.New("step limit exceeded")
+	// ErrUnknownSubTree is returned when a NKSubTree node refers to a subtree that does not exist.
+	ErrUnknownSubTree = errors.New("unknown subtree")
+	// ErrNowhereToReturn is returned when a NKReturn node is reached outside a subtree.
+	ErrNowhereToReturn = errors.New("nowhere to /return to")
+	// ErrMergeFailed is returned when two trees cannot be merged, usually because the roots differ.
+	ErrMergeFailed = errors.New("failed to merge trees")
+	// ErrMissingInfixes is returned when inserting a verb without infix positions.
+	ErrMissingInfixes = errors.New("verb has no infix positions")
+	// ErrUnknownFlag is returned when inserting an entry with a flag that is not supported.
+	ErrUnknownFlag = errors.New("unknown flag")
+	// ErrUnknownPhraseWord is returned when inserting a phrase with slots or gaps that has words that are not
+	// found.
+	ErrUnknownPhraseWord = errors.New("phrase has unknown words")
+
+	// ErrUnknownLetter is the cause of a PhonotacticError for a letter that is not in Na'vi.
+	ErrUnknownLetter = errors.New("unknown letter")
+	// ErrNoVowel is the cause of a PhonotacticError for a word without a vowel or pseudovowel.
+	ErrNoVowel = errors.New("no vowel")
+	// ErrIllegalOnset is the cause of a PhonotacticError for consonants that cannot start the word.
+	ErrIllegalOnset = errors.New("illegal onset")
+	// ErrIllegalCoda is the cause of a PhonotacticError for consonants that cannot end the word.
+	ErrIllegalCoda = errors.New("illegal coda")
+	// ErrIllegalCluster is the cause of a PhonotacticError for consonants between two vowels that cannot
+	// be split into a coda and an onset.
+	ErrIllegalCluster = errors.New("illegal consonant cluster")
+)
+
+// LimitError is returned alongside partial results when a lookup is stopped early. Err is either
+// ErrStepLimitExceeded or the error from the context.
+type LimitError struct {
+	StepCount int64
+	Err       error
+}
+
+func (err *LimitError) Error() string {
+	return fmt.Sprintf("lookup stopped after %d steps: %s", err.StepCount, err.Err)
+}
+
+func (err *LimitError) Unwrap() error {
+	return err.Err
+}
+
+// SubTreeError is returned by the runner when the tree cannot be executed. An empty Name means that
+// it happened outside any subtree.
+type SubTreeError struct {
+	Name string
+	Err  error
+}
+
+func (err *SubTreeError) Error() string {
+	if err.Name == "" {
+		return fmt.Sprintf("root tree: %s", err.Err)
+	}
+
+	return fmt.Sprintf("subtree $%s: %s", err.Name, err.Err)
+}
+
+func (err *SubTreeError) Unwrap() error {
+	return err.Err
+}
+
+// EntryError is returned when an entry could not be inserted into a Dictionary.
+type EntryError struct {
+	ID   string
+	Word string
+	Err  error
+}
+
+func (err *EntryError) Error() string {
+	return fmt.Sprintf("entry %s (%s): %s", err.ID, err.Word, err.Err)
+}
+
+func (err *EntryError) Unwrap() error {
+	return err.Err
+}
+
+// PhonotacticError is returned by Syllabify and ValidateWord when the word is not a valid Na'vi word. The
+// offset is the byte offset in the lowercase word where the problem is.
+type PhonotacticError struct {
+	Word   string
+	Offset int
+	Err    error
+}
+
+func (err *PhonotacticError) Error() string {
+	return fmt.Sprintf("word %s at %d: %s", err.Word, err.Offset, err.Err)
+}
+
+func (err *PhonotacticError) Unwrap() error {
+	return err.Err
+}
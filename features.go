@@ -0,0 +1,232 @@
+package lutral
+
+// Features are the grammatical features that the affixes of a Result stand for. Fields are left empty when
+// no affix says anything about them, e.g. an unaffixed noun has no Case even though it is subjective.
+type Features struct {
+	Case       Case       `json:"case,omitempty"`
+	Number     Number     `json:"number,omitempty"`
+	Tense      Tense      `json:"tense,omitempty"`
+	Aspect     Aspect     `json:"aspect,omitempty"`
+	Mood       Mood       `json:"mood,omitempty"`
+	Voice      Voice      `json:"voice,omitempty"`
+	Affect     Affect     `json:"affect,omitempty"`
+	Evidential Evidential `json:"evidential,omitempty"`
+	Determiner Determiner `json:"determiner,omitempty"`
+	Negated    bool       `json:"negated,omitempty"`
+	Question   bool       `json:"question,omitempty"`
+}
+
+type Case string
+
+const (
+	CaseAgentive   Case = "agentive"
+	CasePatientive Case = "patientive"
+	CaseDative     Case = "dative"
+	CaseGenitive   Case = "genitive"
+	CaseTopical    Case = "topical"
+)
+
+type Number string
+
+const (
+	NumberDual   Number = "dual"
+	NumberTrial  Number = "trial"
+	NumberPlural Number = "plural"
+)
+
+type Tense string
+
+const (
+	TensePast       Tense = "past"
+	TenseRecentPast Tense = "recent-past"
+	TenseNearFuture Tense = "near-future"
+	TenseFuture     Tense = "future"
+)
+
+type Aspect string
+
+const (
+	AspectPerfective   Aspect = "perfective"
+	AspectImperfective Aspect = "imperfective"
+)
+
+type Mood string
+
+const (
+	MoodSubjunctive Mood = "subjunctive"
+	MoodIntentional Mood = "intentional"
+	MoodImperative  Mood = "imperative"
+)
+
+type Voice string
+
+const (
+	VoiceReflexive          Voice = "reflexive"
+	VoiceCausative          Voice = "causative"
+	VoiceReflexiveCausative Voice = "reflexive-causative"
+	VoiceActive             Voice = "active"
+	VoicePassive            Voice = "passive"
+)
+
+type Affect string
+
+const (
+	AffectLaudative  Affect = "laudative"
+	AffectPejorative Affect = "pejorative"
+	AffectCeremonial Affect = "ceremonial"
+)
+
+type Evidential string
+
+const (
+	EvidentialInferential Evidential = "inferential"
+)
+
+type Determiner string
+
+const (
+	DeterminerProximal   Determiner = "proximal"
+	DeterminerDistal     Determiner = "distal"
+	DeterminerUniversal  Determiner = "universal"
+	DeterminerIndefinite Determiner = "indefinite"
+)
+
+// Features looks up the features of each affix. Affixes without features (e.g. -sì or the adpositions)
+// are left out.
+func (result *Result) Features() Features {
+	features := Features{}
+	for _, prefix := range result.Prefixes {
+		features.add(affixFeatures[prefix+"-"])
+	}
+	for _, infix := range result.Infixes {
+		features.add(affixFeatures["<"+infix+">"])
+	}
+	for _, suffix := range result.Suffixes {
+		features.add(affixFeatures["-"+suffix])
+	}
+	for _, particle := range result.Particles {
+		features.add(affixFeatures["["+particle+"]"])
+	}
+
+	return features
+}
+
+// add sets the fields that are set in the other, the latter affix wins for the same field.
+func (features *Features) add(other Features) {
+	if other.Case != "" {
+		features.Case = other.Case
+	}
+	if other.Number != "" {
+		features.Number = other.Number
+	}
+	if other.Tense != "" {
+		features.Tense = other.Tense
+	}
+	if other.Aspect != "" {
+		features.Aspect = other.Aspect
+	}
+	if other.Mood != "" {
+		features.Mood = other.Mood
+	}
+	if other.Voice != "" {
+		features.Voice = other.Voice
+	}
+	if other.Affect != "" {
+		features.Affect = other.Affect
+	}
+	if other.Evidential != "" {
+		features.Evidential = other.Evidential
+	}
+	if other.Determiner != "" {
+		features.Determiner = other.Determiner
+	}
+	features.Negated = features.Negated || other.Negated
+	features.Question = features.Question || other.Question
+}
+
+// affixFeatures maps the affixes, written as nodes (e.g. "ay-", "<ay>", "-ìl" and "[ke]"), to their features.
+// Every affix name in the default grammar must be in here, even if it has no features.
+var affixFeatures = map[string]Features{
+	// Noun prefixes
+	"me-":     {Number: NumberDual},
+	"pxe-":    {Number: NumberTrial},
+	"ay-":     {Number: NumberPlural},
+	"fì-":     {Determiner: DeterminerProximal},
+	"tsa-":    {Determiner: DeterminerDistal},
+	"pe-":     {Question: true},
+	"fay-":    {Number: NumberPlural, Determiner: DeterminerProximal},
+	"pay-":    {Number: NumberPlural, Question: true},
+	"fra-":    {Determiner: DeterminerUniversal},
+	"fne-":    {},
+	"sna-":    {},
+	"munsna-": {},
+	"a-":      {},
+	"nì-":     {},
+	"tì-":     {},
+	"sä-":     {},
+	"le-":     {},
+	"tsuk-":   {},
+	"ketsuk-": {Negated: true},
+	"-a":      {},
+	"-sì":     {},
+	"-ve":     {},
+	"-nga'":   {},
+	"-tswo":   {},
+	"-yu":     {},
+	"-y":      {},
+	"-fkeyk":  {},
+	"-tsyìp":  {},
+	"-o":      {Determiner: DeterminerIndefinite},
+	"-pe":     {Question: true},
+	"-to":     {},
+	"-l":      {Case: CaseAgentive},
+	"-ìl":     {Case: CaseAgentive},
+	"-t":      {Case: CasePatientive},
+	"-ti":     {Case: CasePatientive},
+	"-it":     {Case: CasePatientive},
+	"-r":      {Case: CaseDative},
+	"-ru":     {Case: CaseDative},
+	"-ur":     {Case: CaseDative},
+	"-ri":     {Case: CaseTopical},
+	"-ìri":    {Case: CaseTopical},
+	"-yä":     {Case: CaseGenitive},
+	"-ä":      {Case: CaseGenitive},
+	"[ke]":    {Negated: true},
+	"[rä'ä]":  {Negated: true, Mood: MoodImperative},
+	"<äpeyk>": {Voice: VoiceReflexiveCausative},
+	"<äp>":    {Voice: VoiceReflexive},
+	"<eyk>":   {Voice: VoiceCausative},
+	"<us>":    {Voice: VoiceActive},
+	"<awn>":   {Voice: VoicePassive},
+	"<iv>":    {Mood: MoodSubjunctive},
+	"<irv>":   {Mood: MoodSubjunctive, Aspect: AspectImperfective},
+	"<ilv>":   {Mood: MoodSubjunctive, Aspect: AspectPerfective},
+	"<imv>":   {Mood: MoodSubjunctive, Tense: TensePast},
+	"<iyev>":  {Mood: MoodSubjunctive, Tense: TenseFuture},
+	"<ìyev>":  {Mood: MoodSubjunctive, Tense: TenseNearFuture},
+	"<am>":    {Tense: TensePast},
+	"<ìm>":    {Tense: TenseRecentPast},
+	"<ìy>":    {Tense: TenseNearFuture},
+	"<ay>":    {Tense: TenseFuture},
+	"<ìsy>":   {Tense: TenseNearFuture, Mood: MoodIntentional},
+	"<asy>":   {Tense: TenseFuture, Mood: MoodIntentional},
+	"<er>":    {Aspect: AspectImperfective},
+	"<arm>":   {Tense: TensePast, Aspect: AspectImperfective},
+	"<ìrm>":   {Tense: TenseRecentPast, Aspect: AspectImperfective},
+	"<ìry>":   {Tense: TenseNearFuture, Aspect: AspectImperfective},
+	"<ary>":   {Tense: TenseFuture, Aspect: AspectImperfective},
+	"<ol>":    {Aspect: AspectPerfective},
+	"<alm>":   {Tense: TensePast, Aspect: AspectPerfective},
+	"<ìlm>":   {Tense: TenseRecentPast, Aspect: AspectPerfective},
+	"<ìly>":   {Tense: TenseNearFuture, Aspect: AspectPerfective},
+	"<aly>":   {Tense: TenseFuture, Aspect: AspectPerfective},
+	"<ei>":    {Affect: AffectLaudative},
+	"<eiy>":   {Affect: AffectLaudative},
+	"<äng>":   {Affect: AffectPejorative},
+	"<uy>":    {Affect: AffectCeremonial},
+	"<ats>":   {Evidential: EvidentialInferential},
+
+	// The infix list has these as single tokens, so they need their own entries.
+	"<ìsh=ìsy>": {Tense: TenseNearFuture, Mood: MoodIntentional},
+	"<ash=asy>": {Tense: TenseFuture, Mood: MoodIntentional},
+}
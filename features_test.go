@@ -0,0 +1,104 @@
+package lutral
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestResult_Features(t *testing.T) {
+	dict := miniDict()
+
+	table := []struct {
+		Lookup   string
+		Expected Features
+	}{
+		{"kaltxì", Features{}},
+		{"ayfneikranur", Features{Number: NumberPlural, Case: CaseDative}},
+		{"fepesìfmusetoktsyìpoka", Features{Question: true, Number: NumberTrial, Voice: VoiceActive, Determiner: DeterminerIndefinite}},
+		{"täpeykìyeverkeiup", Features{Voice: VoiceReflexiveCausative, Mood: MoodSubjunctive, Tense: TenseNearFuture, Affect: AffectLaudative}},
+		{"fe'erul", Features{Aspect: AspectImperfective}},
+		{"fmäpetok", Features{Voice: VoiceReflexive}},
+		{"fmirvetok", Features{Mood: MoodSubjunctive, Aspect: AspectImperfective}},
+		{"fmilvetok", Features{Mood: MoodSubjunctive, Aspect: AspectPerfective}},
+		{"tsayuvane", Features{Determiner: DeterminerDistal, Number: NumberPlural, Case: CaseGenitive}},
+	}
+
+	for _, row := range table {
+		t.Run(row.Lookup, func(t *testing.T) {
+			res := dict.Lookup(row.Lookup)
+			if !assert.NotEmpty(t, res) {
+				return
+			}
+
+			assert.Equal(t, row.Expected, res[0].Features())
+		})
+	}
+
+	res := Result{ID: "1", Particles: []string{"rä'ä"}, Infixes: []string{"ats"}}
+	assert.Equal(t, Features{Negated: true, Mood: MoodImperative, Evidential: EvidentialInferential}, res.Features())
+}
+
+// TestAffixFeatures_Coverage fails when an affix is added to the grammar or generators without being
+// added to affixFeatures.
+func TestAffixFeatures_Coverage(t *testing.T) {
+	trees := []*Node{
+		VerbFromEntry(*ParseEntry("392:fm<0><1>et<2>ok:vtr.")),
+		VerbFromEntry(*ParseEntry("2648:uvan s<0><1><2>i:vin.")),
+		NounFromEntry(*ParseEntry("604:ikran:n.")),
+		AdjectiveFromEntry(*ParseEntry("10124:fe'lup:adj.")),
+		NumeralTree(),
+	}
+	for _, tree := range GenerateInitialSubTreeMap() {
+		trees = append(trees, tree)
+	}
+
+	for _, tree := range trees {
+		walkAffixes(tree, func(affix string) {
+			_, ok := affixFeatures[affix]
+			assert.True(t, ok, "affix %s has no features", affix)
+		})
+	}
+
+	for name, list := range infixMap {
+		for _, infix := range list {
+			if infix.Name != "" {
+				_, ok := affixFeatures["<"+infix.Name+">"]
+				assert.True(t, ok, "infix <%s> in list %s has no features", infix.Name, name)
+			}
+		}
+	}
+}
+
+func walkAffixes(node *Node, cb func(affix string)) {
+	switch node.Kind {
+	case NKPrefix:
+		cb(strings.TrimSuffix(node.Value, "+") + "-")
+	case NKSuffix:
+		suffix, suffixName, hasAlias := strings.Cut(node.Value, "=")
+		if !hasAlias {
+			suffixName = suffix
+		}
+		cb("-" + suffixName)
+	case NKParticle:
+		particle, particleName, hasOverride := strings.Cut(node.Value, "=")
+		if !hasOverride {
+			particleName = particle
+		}
+		cb("[" + particleName + "]")
+	case NKInfix:
+		for _, infix := range infixes(infixMap, strings.Split(node.Value, ",")...) {
+			if infix.Name != "" {
+				cb("<" + infix.Name + ">")
+			}
+		}
+	case NKNumeral:
+		if node.Value != "" {
+			cb("-" + node.Value)
+		}
+	}
+
+	for i := range node.Children {
+		walkAffixes(&node.Children[i], cb)
+	}
+}
@@ -0,0 +1,214 @@
+package lutral
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrGrammarSyntax is the cause of errors from ParseGrammar.
+var ErrGrammarSyntax = errors.New("grammar syntax error")
+
+// Grammar holds the subtrees and infix lists that make up the affix grammar.
+type Grammar struct {
+	SubTreeMap map[string]*Node
+	// InfixLists holds the infixes in the notation used by NKInfix, e.g. "er -rr" or "epeyk =äpeyk".
+	InfixLists map[string][]string
+}
+
+// ParseGrammar reads a grammar file. The file is made up of sections that start with a header line:
+//
+//	subtree <name>
+//	infixes <name>
+//
+// In a subtree, each following line is the arguments of one BuildTree call separated by whitespace,
+// and the subtree is all of them combined. An argument can be quoted with "" if it contains spaces or
+// is empty. A subtree without any lines is empty, which is useful for ones filled by Dictionary.
+//
+// In an infix list, each following line is one infix, with "" being the empty infix.
+//
+// Lines starting with // are comments, and blank lines are ignored. See default.grammar for the
+// grammar used when nothing else is provided.
+func ParseGrammar(r io.Reader) (*Grammar, error) {
+	grammar := &Grammar{
+		SubTreeMap: make(map[string]*Node),
+		InfixLists: make(map[string][]string),
+	}
+
+	var subTreeName, infixListName string
+	var trees []*Node
+
+	finishSubTree := func() error {
+		if subTreeName == "" {
+			return nil
+		}
+
+		tree := EmptyTree()
+		if len(trees) > 0 {
+			var err error
+			if tree, err = TryCombineTrees(trees...); err != nil {
+				return fmt.Errorf("subtree %s: %w", subTreeName, err)
+			}
+		}
+
+		grammar.SubTreeMap[subTreeName] = tree
+		subTreeName = ""
+		trees = nil
+
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber += 1
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		keyword, name, _ := strings.Cut(line, " ")
+		switch keyword {
+		case "subtree", "infixes":
+			if err := finishSubTree(); err != nil {
+				return nil, err
+			}
+
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return nil, fmt.Errorf("%w: line %d: %s without a name", ErrGrammarSyntax, lineNumber, keyword)
+			}
+			if _, exists := grammar.SubTreeMap[name]; exists && keyword == "subtree" {
+				return nil, fmt.Errorf("%w: line %d: subtree %s is defined twice", ErrGrammarSyntax, lineNumber, name)
+			}
+			if _, exists := grammar.InfixLists[name]; exists && keyword == "infixes" {
+				return nil, fmt.Errorf("%w: line %d: infixes %s is defined twice", ErrGrammarSyntax, lineNumber, name)
+			}
+
+			if keyword == "subtree" {
+				subTreeName = name
+				infixListName = ""
+			} else {
+				infixListName = name
+				grammar.InfixLists[name] = []string{}
+			}
+
+			continue
+		}
+
+		switch {
+		case subTreeName != "":
+			args, err := splitGrammarLine(line)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %s", ErrGrammarSyntax, lineNumber, err)
+			}
+
+			trees = append(trees, BuildTree(args...))
+		case infixListName != "":
+			if line == `""` {
+				line = ""
+			}
+
+			grammar.InfixLists[infixListName] = append(grammar.InfixLists[infixListName], line)
+		default:
+			return nil, fmt.Errorf("%w: line %d: %q is outside any subtree or infix list", ErrGrammarSyntax, lineNumber, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := finishSubTree(); err != nil {
+		return nil, err
+	}
+
+	return grammar, nil
+}
+
+// ApplyGrammar replaces the dictionary's subtrees and infix lists with the ones in the grammar, leaving
+// the rest as they were. It should be done before inserting entries, since some subtrees (e.g. nsadp)
+// are filled during insertion.
+func (dictionary *Dictionary) ApplyGrammar(grammar *Grammar) {
+	if dictionary.SubTreeMap == nil {
+		dictionary.SubTreeMap = GenerateInitialSubTreeMap()
+	}
+	for name, tree := range grammar.SubTreeMap {
+		dictionary.SubTreeMap[name] = CopyTree(*tree)
+	}
+
+	if len(grammar.InfixLists) > 0 {
+		if dictionary.InfixLists == nil {
+			dictionary.InfixLists = make(map[string][]string, len(grammar.InfixLists))
+		}
+		for name, list := range grammar.InfixLists {
+			dictionary.InfixLists[name] = append(list[:0:0], list...)
+		}
+
+		dictionary.infixMap = compileInfixLists(dictionary.InfixLists)
+	}
+}
+
+// splitGrammarLine splits the line by whitespace, keeping quoted parts together.
+func splitGrammarLine(line string) ([]string, error) {
+	var res []string
+
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		if strings.HasPrefix(line, `"`) {
+			end := strings.IndexByte(line[1:], '"')
+			if end == -1 {
+				return nil, errors.New("unterminated quote")
+			}
+
+			res = append(res, line[1:end+1])
+			line = line[end+2:]
+		} else {
+			end := strings.IndexAny(line, " \t")
+			if end == -1 {
+				end = len(line)
+			}
+
+			res = append(res, line[:end])
+			line = line[end:]
+		}
+	}
+
+	return res, nil
+}
+
+// compileInfixLists builds the infix map used by the runner, where the lists override the default ones.
+func compileInfixLists(lists map[string][]string) map[string][]infix {
+	res := make(map[string][]infix, len(infixMap)+len(lists))
+	for name, list := range infixMap {
+		res[name] = list
+	}
+	for name, list := range buildInfixMap(lists) {
+		res[name] = list
+	}
+
+	return res
+}
+
+func buildInfixMap(lists map[string][]string) map[string][]infix {
+	res := make(map[string][]infix, len(lists))
+	for name, list := range lists {
+		res[name] = sortedInfixes(infixes(nil, list...))
+	}
+
+	return res
+}
+
+//go:embed default.grammar
+var defaultGrammarFile string
+
+var defaultGrammar = mustParseGrammar(defaultGrammarFile)
+
+func mustParseGrammar(s string) *Grammar {
+	grammar, err := ParseGrammar(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+
+	return grammar
+}
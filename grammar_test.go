@@ -0,0 +1,117 @@
+package lutral
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseGrammar(t *testing.T) {
+	grammar, err := ParseGrammar(strings.NewReader(`
+// Comments and blank lines are skipped.
+subtree nsto
+	-to /return
+	-to "-sì" /return
+
+subtree empty
+
+infixes 3
+	""
+	uy
+	er -rr
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, CombineTrees(
+		BuildTree("-to", "/return"),
+		BuildTree("-to", "-sì", "/return"),
+	), grammar.SubTreeMap["nsto"])
+	assert.Equal(t, EmptyTree(), grammar.SubTreeMap["empty"])
+	assert.Equal(t, []string{"", "uy", "er -rr"}, grammar.InfixLists["3"])
+}
+
+func TestParseGrammar_Errors(t *testing.T) {
+	table := []struct {
+		Input string
+		Error string
+	}{
+		{"-to /return", `grammar syntax error: line 1: "-to /return" is outside any subtree or infix list`},
+		{"subtree\n", "grammar syntax error: line 1: subtree without a name"},
+		{"subtree a\n\t\"-to /return", "grammar syntax error: line 2: unterminated quote"},
+		{"subtree a\nsubtree a", "grammar syntax error: line 2: subtree a is defined twice"},
+		{"infixes 1\ninfixes 1", "grammar syntax error: line 2: infixes 1 is defined twice"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Input, func(t *testing.T) {
+			_, err := ParseGrammar(strings.NewReader(row.Input))
+			assert.ErrorIs(t, err, ErrGrammarSyntax)
+			assert.EqualError(t, err, row.Error)
+		})
+	}
+}
+
+func TestDictionary_ApplyGrammar(t *testing.T) {
+	grammar, err := ParseGrammar(strings.NewReader(`
+// A made-up case ending for consonants
+subtree ncec
+	/return
+	-ìl|-ti|-it|-ur|-ìri|-ä|-e=ä|-ìtsa /return
+
+// A made-up infix in the last position
+infixes 2
+	""
+	ei
+	olo
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	dict := &Dictionary{}
+	dict.ApplyGrammar(grammar)
+	dict.Insert(*ParseEntry("604:ikran:n."))
+	dict.Insert(*ParseEntry("392:fm<0><1>et<2>ok:vtr."))
+	assert.NoError(t, dict.Validate())
+
+	table := []struct {
+		Lookup   string
+		Expected string
+	}{
+		{"ikranìtsa", "604 -ìtsa"},
+		{"ikranìl", "604 -ìl"},
+		{"fmetolook", "392 <olo>"},
+		{"fmeteiok", "392 <ei>"},
+		{"fmetängok", ""},
+	}
+
+	for _, row := range table {
+		t.Run(row.Lookup, func(t *testing.T) {
+			resStr := make([]string, 0, 1)
+			for _, res := range dict.Lookup(row.Lookup) {
+				resStr = append(resStr, res.String())
+			}
+
+			assert.Equal(t, row.Expected, strings.Join(resStr, ";"))
+		})
+	}
+
+	// The runners only read the compiled infixes, so lookups can run at the same time.
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "392 <olo>", dict.Lookup("fmetolook")[0].String())
+		}()
+	}
+	wg.Wait()
+
+	// The default dictionary should not be affected.
+	assert.Equal(t, fmt.Sprint(infixes(infixMap, "2")), fmt.Sprint(infixes(compileInfixLists(nil), "2")))
+	assert.Empty(t, miniDict().Lookup("ikranìtsa"))
+}
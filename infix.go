@@ -60,26 +60,5 @@ func sortedInfixes(infixes []infix) []infix {
 	return infixes
 }
 
-var infixMap = map[string][]infix{
-	"0": sortedInfixes(infixes(nil,
-		"",
-		"äpeyk", "epeyk =äpeyk",
-		"äp", "ep =äp",
-		"eyk",
-	)),
-	"1": sortedInfixes(infixes(nil,
-		"",
-		"iv", "irv", "ilv", "imv", "iyev", "ìyev",
-		"am", "ìm", "ìy", "ay",
-		"ìsy", "ìsh=ìsy", "asy", "ash=asy",
-		"er -rr", "arm", "ìrm", "ìry", "ary",
-		"ol -ll", "alm", "ìlm", "ìly", "aly",
-	)),
-	"2": sortedInfixes(infixes(nil,
-		"",
-		"eiy >i >ì >rr >ll", "ei",
-		"äng", "eng =äng",
-		"ats",
-		"uy",
-	)),
-}
+// infixMap holds the infix lists from the default grammar.
+var infixMap = buildInfixMap(defaultGrammar.InfixLists)
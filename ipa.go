@@ -0,0 +1,130 @@
+package lutral
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// IPA transcribes the text to broad IPA, e.g. "kxanì" becomes "kʼanɪ". It takes both the Forest and the
+// Reef spellings. Stress is left out, since it's not in the spelling, see EntryIPA and
+// Dictionary.ResultIPA for that.
+func IPA(text string) string {
+	return transcribeIPA(strings.ToLower(text), nil)
+}
+
+// EntryIPA transcribes the entry's word with the syllable breaks and stress, if the entry has them. As in
+// IPA, the stress mark stands in for the syllable break before the stressed syllable.
+func EntryIPA(entry Entry) string {
+	word := strings.ToLower(entry.Word)
+	if len(entry.Syllables) < 2 {
+		return transcribeIPA(word, nil)
+	}
+
+	marks := make(map[int]string, len(entry.Syllables))
+	for i, offset := range entry.Syllables[1:] {
+		if offset > 0 && word[offset-1] != ' ' {
+			marks[offset] = "."
+		}
+		if entry.Stress != nil && *entry.Stress == i+1 {
+			marks[offset] = "ˈ"
+		}
+	}
+	if entry.Stress != nil && *entry.Stress == 0 {
+		marks[0] = "ˈ"
+	}
+
+	return transcribeIPA(word, marks)
+}
+
+// ResultIPA transcribes the form of a result from Extract. The stress is marked if the entry knows it and
+// the entry's word can be found unchanged in the form, i.e. not for lenited or infixed words.
+func (dictionary *Dictionary) ResultIPA(result Result) string {
+	form := strings.ToLower(result.Form)
+
+	lemma, ok := dictionary.Lemmas[result.ID]
+	if !ok || lemma.Stress == nil || len(lemma.Syllables) < 2 {
+		return transcribeIPA(form, nil)
+	}
+
+	offset := strings.Index(form, strings.ToLower(lemma.Word))
+	if offset == -1 {
+		return transcribeIPA(form, nil)
+	}
+
+	return transcribeIPA(form, map[int]string{offset + lemma.Syllables[*lemma.Stress]: "ˈ"})
+}
+
+// transcribeIPA transcribes the lowercase word, putting the marks before the letters at their byte offsets.
+func transcribeIPA(word string, marks map[int]string) string {
+	sb := strings.Builder{}
+	sb.Grow(len(word) * 2)
+
+	for offset := 0; offset < len(word); {
+		letter, ipa := nextIPALetter(word[offset:])
+		for i := offset + 1; i < offset+len(letter); i++ {
+			// A syllable break splits the letter, e.g. ta.wu.
+			if marks[i] != "" {
+				_, size := utf8.DecodeRuneInString(letter)
+				letter, ipa = nextIPALetter(letter[:size])
+				break
+			}
+		}
+
+		sb.WriteString(marks[offset])
+		sb.WriteString(ipa)
+		offset += len(letter)
+	}
+
+	return sb.String()
+}
+
+// nextIPALetter finds the letter at the start of the text and its IPA. Anything that is not in ipaLetters
+// is kept as it is.
+func nextIPALetter(text string) (letter, ipa string) {
+	for _, kv := range ipaLetters {
+		if strings.HasPrefix(text, kv[0]) {
+			return kv[0], kv[1]
+		}
+	}
+
+	for _, ch := range text {
+		return string(ch), string(ch)
+	}
+
+	return "", ""
+}
+
+// ipaLetters are the letters and their IPA, with the longer letters first.
+var ipaLetters = [][2]string{
+	// Reef writes n-g for n followed by g, since ng is its own letter.
+	{"n-g", "ng"},
+
+	{"px", "pʼ"},
+	{"tx", "tʼ"},
+	{"kx", "kʼ"},
+	{"ts", "ts"},
+	{"ng", "ŋ"},
+	{"rr", "r̩"},
+	{"ll", "l̩"},
+	{"aw", "aw"},
+	{"ay", "aj"},
+	{"ew", "ɛw"},
+	{"ey", "ɛj"},
+	// Reef spellings of tsy and sy.
+	{"ch", "tʃ"},
+	{"sh", "ʃ"},
+
+	{"a", "a"},
+	{"ä", "æ"},
+	{"e", "ɛ"},
+	{"é", "e"},
+	{"i", "i"},
+	{"ì", "ɪ"},
+	{"o", "o"},
+	{"u", "u"},
+	{"ù", "ʊ"},
+	{"'", "ʔ"},
+	{"r", "ɾ"},
+	{"y", "j"},
+	{"-", ""},
+}
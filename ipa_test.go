@@ -0,0 +1,85 @@
+package lutral
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPA(t *testing.T) {
+	table := []struct {
+		Text     string
+		Expected string
+	}{
+		{"kxanì", "kʼanɪ"},
+		{"Kaltxì", "kaltʼɪ"},
+		{"pxel", "pʼɛl"},
+		{"ngä'än", "ŋæʔæn"},
+		{"tsyal", "tsjal"},
+		{"tìsyìmawnun'i", "tɪsjɪmawnunʔi"},
+		{"frrfen", "fr̩fɛn"},
+		{"kllte", "kl̩tɛ"},
+		{"ayoeng", "ajoɛŋ"},
+		{"tsawke", "tsawkɛ"},
+		{"'asap-susi", "ʔasapsusi"},
+		{"ganì", "ganɪ"},
+		{"chal", "tʃal"},
+		{"shaw", "ʃaw"},
+		{"sän-g", "sæng"},
+		{"eltu herahaw", "ɛltu hɛɾahaw"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Text, func(t *testing.T) {
+			assert.Equal(t, row.Expected, IPA(row.Text))
+		})
+	}
+}
+
+func TestEntryIPA(t *testing.T) {
+	table := []struct {
+		Entry    string
+		Expected string
+	}{
+		{"464:ˈf<0><1>rr.f<2>en:vtr.", "ˈfr̩.fɛn"},
+		{"-1:ha.me.ˈtsì:n.", "ha.mɛˈtsɪ"},
+		{"-2:ˈtxawn.ulsrung a tswa.yon:n.", "ˈtʼawn.ulsɾuŋ a tswa.jon"},
+		{"-3:tsko swi.ˈzaw*:n.", "tsko swiˈzaw"},
+		{"-4:ta.wu:n.", "ta.wu"},
+		{"-5:kxanì:adj.", "kʼanɪ"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Entry, func(t *testing.T) {
+			assert.Equal(t, row.Expected, EntryIPA(*ParseEntry(row.Entry)))
+		})
+	}
+}
+
+func TestDictionary_ResultIPA(t *testing.T) {
+	dict := Dictionary{KeepLemmas: true}
+	dict.Insert(*ParseEntry("-1:ha.me.ˈtsì:n."))
+	dict.Insert(*ParseEntry("-2:ˈt<0><1>a.r<2>on:vtr."))
+	dict.Insert(*ParseEntry("-3:ˈtxe.'lan:n."))
+
+	table := []struct {
+		Text     string
+		Expected string
+	}{
+		{"hametsì", "hamɛˈtsɪ"},
+		{"ayhametsìl", "ajhamɛˈtsɪl"},
+		{"taron", "ˈtaɾon"},
+		{"tolaron", "tolaɾon"},
+		{"txe'lanti", "ˈtʼɛʔlanti"},
+		{"te'lanti", "tɛʔlanti"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Text, func(t *testing.T) {
+			res := dict.Extract(row.Text)
+			if assert.NotEmpty(t, res) {
+				assert.Equal(t, row.Expected, dict.ResultIPA(res[0]))
+			}
+		})
+	}
+}
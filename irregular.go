@@ -0,0 +1,156 @@
+package lutral
+
+import (
+	"slices"
+	"strings"
+)
+
+// IrregularForm is a form that does not follow the rules, e.g. oeyä for oe with -yä. It replaces the
+// regular form with the same affixes. In the entry format, it is the flag "irr:oeyä=-yä", where the
+// affixes are written as nodes (ay+, fne-, -yä, <us>, [ke]) separated by spaces, along with the lenition
+// if the form has one after a prefix (e.g. "irr:ayfayyä=ay+ -ä p→f"). If the entry has more
+// than one PoS, the one the form is of can be put first (e.g. "irr:n.:ikranyä=-ä"), or else it is the
+// first one.
+type IrregularForm struct {
+	Form    string
+	PoS     string
+	Affixes []string
+}
+
+// parseIrregularForm parses the part of the irr: flag after the prefix.
+func parseIrregularForm(s string) (IrregularForm, bool) {
+	form, affixes, ok := strings.Cut(s, "=")
+	if !ok || form == "" {
+		return IrregularForm{}, false
+	}
+
+	pos := ""
+	if before, after, ok := strings.Cut(form, ":"); ok {
+		pos, form = before, after
+		if pos == "" || form == "" {
+			return IrregularForm{}, false
+		}
+	}
+
+	return IrregularForm{Form: form, PoS: pos, Affixes: strings.Fields(affixes)}, true
+}
+
+// IrregularFormsFromEntry generates the trees for the irregular forms. They are matched as they are, with
+// an NKIrregular node to add the affixes. Like the regular forms, they are also matched in the alternative
+// spellings, unless the entry has the "reef" flag.
+func IrregularFormsFromEntry(entry Entry) *Node {
+	res := EmptyTree()
+	for _, irregular := range entry.Irregulars {
+		spellings := WithAlternativeSpellingVariants(strings.ToLower(irregular.Form))
+		if entry.HasFlag("reef") {
+			spellings = []SpellingVariant{{Text: spellings[0].Text, Spelling: SpellingReef}}
+		}
+
+		for _, spelling := range spellings {
+			tree := BuildTree(
+				spelling.Text,
+				"~"+strings.Join(irregular.Affixes, " "),
+			).AndThenResult(irregularResultValue(entry, irregular))
+
+			tagSpelling(tree, spelling.Spelling)
+			res.MergeFrom(*tree)
+		}
+	}
+
+	return res
+}
+
+// IrregularResultsFromEntry lists what the irregular forms stand for, so that the regular forms with the
+// same affixes can be dropped. This is what goes into Dictionary.Irregulars.
+func IrregularResultsFromEntry(entry Entry) []Result {
+	res := make([]Result, 0, len(entry.Irregulars))
+	for _, irregular := range entry.Irregulars {
+		id, pos, _ := strings.Cut(irregularResultValue(entry, irregular), ":")
+		result := Result{ID: id, PoS: pos}
+		addIrregularAffixes(&result, irregular.Affixes)
+		res = append(res, result)
+
+		// The form is also found lenited, and then it stands for the regular form with the same lenition.
+		if lenition, _ := ApplyLenition(strings.ToLower(irregular.Form)); lenition != "" && len(result.Lenitions) == 0 {
+			lenited := Result{ID: id, PoS: pos, Lenitions: []string{lenition}}
+			addIrregularAffixes(&lenited, irregular.Affixes)
+			res = append(res, lenited)
+		}
+	}
+
+	return res
+}
+
+func irregularResultValue(entry Entry, irregular IrregularForm) string {
+	if len(entry.PoS) > 1 {
+		if irregular.PoS != "" {
+			return entry.ID + ":" + irregular.PoS
+		}
+
+		return entry.ID + ":" + entry.PoS[0]
+	}
+
+	return entry.ID
+}
+
+// addIrregularAffixes adds the affixes in the order they are written, in front of the ones found after.
+func addIrregularAffixes(result *Result, affixes []string) {
+	var lenitions, prefixes, infixes, suffixes, particles []string
+	for _, affix := range affixes {
+		node := ParseNode(affix)
+		switch node.Kind {
+		case NKRaw:
+			if strings.Contains(node.Value, "→") {
+				lenitions = append(lenitions, node.Value)
+			}
+		case NKPrefix:
+			prefixes = append(prefixes, strings.TrimSuffix(node.Value, "+"))
+		case NKInfix:
+			infixes = append(infixes, node.Value)
+		case NKSuffix:
+			suffixes = append(suffixes, node.Value)
+		case NKParticle:
+			particles = append(particles, node.Value)
+		}
+	}
+
+	result.Lenitions = append(lenitions, result.Lenitions...)
+	result.Prefixes = append(prefixes, result.Prefixes...)
+	result.Infixes = append(infixes, result.Infixes...)
+	result.Suffixes = append(suffixes, result.Suffixes...)
+	result.Particles = append(particles, result.Particles...)
+}
+
+// dropReplacedRegulars removes the results after the offset that have an irregular form replacing them.
+func (runner *Runner) dropReplacedRegulars(resOffset int) {
+	next := resOffset
+	for i := resOffset; i < len(runner.res); i++ {
+		res := runner.res[i]
+		if !res.irregular && runner.isReplacedByIrregular(res) {
+			continue
+		}
+
+		res.irregular = false
+		runner.res[next] = res
+		next += 1
+	}
+
+	runner.res = runner.res[:next]
+}
+
+// isReplacedByIrregular checks if an irregular form stands for the result. A lenited result is only replaced
+// if the irregular form is listed with the same lenition.
+func (runner *Runner) isReplacedByIrregular(res Result) bool {
+	for _, irregular := range runner.IrregularMap[res.ID] {
+		if irregular.PoS == res.PoS &&
+			slices.Equal(irregular.Lenitions, res.Lenitions) &&
+			slices.Equal(irregular.Prefixes, res.Prefixes) &&
+			slices.Equal(irregular.Infixes, res.Infixes) &&
+			slices.Equal(irregular.Suffixes, res.Suffixes) &&
+			slices.Equal(irregular.Particles, res.Particles) {
+			return true
+		}
+	}
+
+	return false
+}
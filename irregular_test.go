@@ -0,0 +1,85 @@
+package lutral
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDictionary_Irregulars(t *testing.T) {
+	dict := Dictionary{}
+	dict.Insert(*ParseEntry("-604:ikran:n.:irr:ikranyä=-ä,irr:ayikranyä=ay+ -ä")) // Fake irregulars
+	dict.Insert(*ParseEntry("1380:oe:pn.:irr:oeyä=-yä"))
+	dict.Insert(*ParseEntry("-1:tute:n."))
+	dict.Insert(*ParseEntry("-2:tsmuk:intj.,n.:irr:n.:tsmukyä=-ä"))           // Fake irregular of another PoS than the first
+	dict.Insert(*ParseEntry("-3:pxun:n.:irr:pxunyä=-ä"))                      // Fake irregular with a Reef spelling
+	dict.Insert(*ParseEntry("-4:pay:n.:irr:payyä=-ä,irr:ayfayyä=ay+ -ä p→f")) // Fake irregulars with lenitions
+	dict.Insert(*ParseEntry("-5:kato:n.:irr:atoyä=-ä"))                       // Fake irregular that can't be lenited
+
+	table := []struct {
+		Lookup   string
+		Expected string
+	}{
+		{"ikranyä", "-604 -ä"},
+		{"ikranä", ""},
+		{"ikrane", ""},
+		{"ayikranyä", "-604 ay- -ä"},
+		{"ayikranä", ""},
+		{"fneikranä", "-604 fne- -ä"},
+		{"ikranìl", "-604 -ìl"},
+		{"oeyä", "1380 -yä"},
+		{"tuteyä", "-1 -yä"},
+		{"tsmukyä", "-2:n. -ä"},
+		{"tsmukä", ""},
+		{"tsmuk", "-2:n.;-2:intj."},
+		{"pxunyä", "-3 -ä"},
+		{"bunyä", "-3 -ä"},
+		{"bunä", ""},
+		{"payyä", "-4 -ä"},
+		{"payä", ""},
+		{"fayyä", "-4 -ä p→f"},
+		{"fayä", ""},
+		{"ayfayyä", "-4 ay- -ä p→f"},
+		{"ayfayä", ""},
+		{"fnepayä", "-4 fne- -ä"},
+		{"fayìl", "-4 -ìl p→f"},
+		{"katoä", ""},
+		{"atoyä", "-5 -ä"},
+		{"hatoä", "-5 -ä k→h"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Lookup, func(t *testing.T) {
+			resStr := ""
+			for _, res := range dict.Lookup(row.Lookup) {
+				if len(resStr) != 0 {
+					resStr += ";"
+				}
+				resStr += res.String()
+			}
+
+			assert.Equal(t, row.Expected, resStr)
+		})
+	}
+}
+
+func TestRunner_Extract_Irregulars(t *testing.T) {
+	dict := Dictionary{}
+	dict.Insert(*ParseEntry("-604:ikran:n.:irr:ikranyä=-ä"))
+
+	assert.Equal(t, []Result{
+		{ID: "-604", Position: 1, Suffixes: []string{"ä"}, Form: "ikranyä", Spelling: SpellingStandard},
+		{ID: "-604", Position: 3, Suffixes: []string{"ìl"}, Form: "ikranìl", Spelling: SpellingStandard},
+	}, dict.Extract("ikranyä ikranä ikranìl"))
+}
+
+func TestRunner_Extract_IrregularSpellings(t *testing.T) {
+	dict := Dictionary{}
+	dict.Insert(*ParseEntry("-1:pxun:n.:irr:pxunyä=-ä"))
+	dict.Insert(*ParseEntry("-2:ikran:n.:reef,irr:ikranyä=-ä"))
+
+	assert.Equal(t, []Result{
+		{ID: "-1", Position: 1, Suffixes: []string{"ä"}, Form: "pxunyä", Spelling: SpellingStandard},
+		{ID: "-1", Position: 2, Suffixes: []string{"ä"}, Form: "bunyä", Spelling: SpellingReef},
+		{ID: "-2", Position: 3, Suffixes: []string{"ä"}, Form: "ikranyä", Spelling: SpellingReef},
+	}, dict.Extract("pxunyä bunyä ikranyä"))
+}
@@ -12,13 +12,23 @@ type Node struct {
 	Children []Node   `json:"c,omitempty"`
 }
 
+// MergedWith is like TryMergedWith, but it panics on failure.
 func (node *Node) MergedWith(other Node) *Node {
-	merged := node.MergeFrom(other)
-	if !merged {
-		panic("MergedWith does not allow failure")
+	res, err := node.TryMergedWith(other)
+	if err != nil {
+		panic(err)
 	}
 
-	return node
+	return res
+}
+
+// TryMergedWith runs MergeFrom and returns back the same object, or an error if the trees could not be merged.
+func (node *Node) TryMergedWith(other Node) (*Node, error) {
+	if !node.MergeFrom(other) {
+		return nil, fmt.Errorf("%w: %s into %s", ErrMergeFailed, other.String(), node.String())
+	}
+
+	return node, nil
 }
 
 func (node *Node) MergeFrom(other Node) bool {
@@ -265,6 +275,10 @@ func (node *Node) String() string {
 		return "/hook"
 	case NKParticle:
 		return "[" + node.Value + "]"
+	case NKNumeral:
+		return "#" + node.Value
+	case NKIrregular:
+		return "~" + node.Value
 	default:
 		return fmt.Sprintf("??? (kind: %d, value: %#v)", node.Kind, node.Value)
 	}
@@ -326,7 +340,8 @@ const (
 	// NKRoot indicate that it's a root node. It should not be used further into the tree.
 	NKRoot = iota
 	// NKResult is the final node in a tree that indicates that a dictionary entry has been found.
-	// It should only return if there is not more remaining.
+	// It should only return if there is not more remaining. The value is "ID[:PoS[:BasePoS:Derivation]]",
+	// followed by "@spelling" for the alternative spellings (see Spelling).
 	NKResult
 	// NKRaw matches the actual text.
 	NKRaw
@@ -349,6 +364,12 @@ const (
 	NKLeafHook
 	// NKParticle allows a sub-result with the given criteria
 	NKParticle
+	// NKNumeral matches any octal numeral and sets the result's number, e.g. "#" for mrrvomun. If the value
+	// is "ve", it matches the ordinal instead (mrrvomunve) and adds it as a suffix.
+	NKNumeral
+	// NKIrregular matches nothing, but it adds the affixes in its value (e.g. "~ay+ -yä") to the results
+	// found after it. It marks an irregular form, see IrregularForm.
+	NKIrregular
 )
 
 func ParseNode(s string) Node {
@@ -369,6 +390,10 @@ func ParseNode(s string) Node {
 		return Node{Kind: NKRaw, Value: strings.TrimPrefix(s, "\\")}
 	case strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]"):
 		return Node{Kind: NKParticle, Value: strings.TrimLeft(strings.TrimRight(s, "]"), "[")}
+	case strings.HasPrefix(s, "~"):
+		return Node{Kind: NKIrregular, Value: strings.TrimPrefix(s, "~")}
+	case strings.HasPrefix(s, "#"):
+		return Node{Kind: NKNumeral, Value: strings.TrimPrefix(s, "#")}
 	case s == "/return":
 		return Node{Kind: NKReturn}
 	case s == "/hook":
@@ -380,16 +405,27 @@ func ParseNode(s string) Node {
 	}
 }
 
+// CombineTrees is like TryCombineTrees, but it panics on failure.
 func CombineTrees(trees ...*Node) *Node {
+	res, err := TryCombineTrees(trees...)
+	if err != nil {
+		panic(err)
+	}
+
+	return res
+}
+
+// TryCombineTrees merges all the trees into one root node. They must all be NKRoot nodes.
+func TryCombineTrees(trees ...*Node) (*Node, error) {
 	current := &Node{Kind: NKRoot, Children: make([]Node, 0, len(trees)*2)}
 
-	for _, tree := range trees {
+	for i, tree := range trees {
 		if !current.MergeFrom(*tree) {
-			panic("CombineTree failed to merge one or more of the trees")
+			return nil, fmt.Errorf("%w: tree %d is %s, not /root", ErrMergeFailed, i, tree.String())
 		}
 	}
 
-	return current
+	return current, nil
 }
 
 func EmptyTree() *Node {
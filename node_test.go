@@ -322,3 +322,19 @@ func TestCombineTrees_panics(t *testing.T) {
 		CombineTrees(&Node{Kind: NKRaw, Value: "leykopx", Children: []Node{}})
 	})
 }
+
+func TestTryCombineTrees(t *testing.T) {
+	_, err := TryCombineTrees(BuildTree("uvan", "=2644"), &Node{Kind: NKRaw, Value: "leykopx"})
+	assert.ErrorIs(t, err, ErrMergeFailed)
+	assert.EqualError(t, err, "failed to merge trees: tree 1 is leykopx, not /root")
+
+	tree, err := TryCombineTrees(BuildTree("uvan", "=2644"), BuildTree("utral", "=2628"))
+	assert.NoError(t, err)
+	assert.Equal(t, CombineTrees(BuildTree("uvan", "=2644"), BuildTree("utral", "=2628")), tree)
+}
+
+func TestNode_TryMergedWith(t *testing.T) {
+	_, err := BuildTree("uvan").TryMergedWith(Node{Kind: NKSuffix, Value: "ti"})
+	assert.ErrorIs(t, err, ErrMergeFailed)
+	assert.EqualError(t, err, "failed to merge trees: -ti into /root")
+}
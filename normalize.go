@@ -0,0 +1,91 @@
+package lutral
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeText is the default normalization of the runners. It lowercases the text, composes letters
+// with combining marks (e.g. "a" + U+0308 becomes "ä") with NFC, and replaces the typographic apostrophes
+// with "'".
+func NormalizeText(text string) string {
+	text = norm.NFC.String(strings.ToLower(text))
+	if !strings.ContainsAny(text, apostrophes) {
+		return text
+	}
+
+	return strings.Map(func(ch rune) rune {
+		if strings.ContainsRune(apostrophes, ch) {
+			return '\''
+		}
+
+		return ch
+	}, text)
+}
+
+// foldedLetter gives the letter without the diacritic, or the letter itself if it has none.
+func foldedLetter(ch rune) rune {
+	switch ch {
+	case 'ä':
+		return 'a'
+	case 'ì':
+		return 'i'
+	case 'é':
+		return 'e'
+	case 'ù':
+		return 'u'
+	}
+
+	return ch
+}
+
+// cutPrefix is strings.CutPrefix that also matches folded letters if FoldDiacritics is set. The last
+// return value tells if it needed that.
+func (runner *Runner) cutPrefix(s, prefix string) (after string, found bool, folded bool) {
+	return cutFoldedPrefix(s, prefix, runner.FoldDiacritics)
+}
+
+// cutFoldedPrefix is strings.CutPrefix that also matches folded letters if fold is set, see Runner.cutPrefix.
+func cutFoldedPrefix(s, prefix string, fold bool) (after string, found bool, folded bool) {
+	if after, found := strings.CutPrefix(s, prefix); found || !fold {
+		return after, found, false
+	}
+
+	offset := 0
+	for _, ch := range prefix {
+		actual, size := utf8.DecodeRuneInString(s[offset:])
+		if size == 0 {
+			return s, false, false
+		}
+		if actual != ch {
+			if actual != foldedLetter(ch) {
+				return s, false, false
+			}
+			folded = true
+		}
+
+		offset += size
+	}
+
+	return s[offset:], true, folded
+}
+
+// markFolded sets Folded on the results after the offset.
+func (runner *Runner) markFolded(resOffset int) {
+	for i := range runner.res[resOffset:] {
+		runner.res[i+resOffset].Folded = true
+	}
+}
+
+func (runner *Runner) normalize(text string) string {
+	if runner.Normalize != nil {
+		return runner.Normalize(text)
+	}
+
+	return NormalizeText(text)
+}
+
+// apostrophes are the characters that are written instead of "'" for the tìftang.
+const apostrophes = "\u2019\u2018\u02bc\u2032\u00b4`"
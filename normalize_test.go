@@ -0,0 +1,127 @@
+package lutral
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeText(t *testing.T) {
+	table := []struct {
+		Text     string
+		Expected string
+	}{
+		{"Kaltxì", "kaltxì"},
+		{"fmäpetok", "fmäpetok"},
+		{"tìtseri", "tìtseri"},
+		{"TÌTSERI", "tìtseri"},
+		{"te’lanti", "te'lanti"},
+		{"teʼlanti", "te'lanti"},
+		{"‘awa", "'awa"},
+		{"a\u0308", "ä"},
+		{"TI\u0300TSERI", "tìtseri"},
+		{"ä̈", "ä̈"},
+	}
+
+	for _, row := range table {
+		t.Run(fmt.Sprintf("%q", row.Text), func(t *testing.T) {
+			assert.Equal(t, row.Expected, NormalizeText(row.Text))
+		})
+	}
+}
+
+func TestDictionary_Lookup_Normalized(t *testing.T) {
+	dict := miniDict()
+
+	table := []struct {
+		Lookup   string
+		Fold     bool
+		Expected string
+		Folded   bool
+	}{
+		{"fmäpetok", false, "392 <äp>", false},
+		{"TE’LANTI", false, "2512 -ti tx→t", false},
+		{"fmapetok", false, "", false},
+		{"fmapetok", true, "392 <äp>", true},
+		{"fmäpetok", true, "392 <äp>", false},
+		{"titseri", true, "10368", true},
+		{"TITSERI", true, "10368", true},
+		{"fma\u0308petok", false, "392 <äp>", false},
+		{"kina", false, "", false},
+		{"kina", true, "#:num. (7)", true},
+		{"tsivolsing", true, "#:num. (36)", true},
+		{"tsìvolsìng", true, "#:num. (36)", false},
+		{"tsivomun", true, "#:num. (34)", true},
+		{"tsìvomun", true, "#:num. (34)", false},
+	}
+
+	for _, row := range table {
+		t.Run(fmt.Sprintf("%s (fold: %t)", row.Lookup, row.Fold), func(t *testing.T) {
+			dict.FoldDiacritics = row.Fold
+			defer func() { dict.FoldDiacritics = false }()
+
+			resStr := ""
+			for _, res := range dict.Lookup(row.Lookup) {
+				if len(resStr) != 0 {
+					resStr += ";"
+				}
+				resStr += res.String()
+				assert.Equal(t, row.Folded, res.Folded)
+			}
+
+			assert.Equal(t, row.Expected, resStr)
+		})
+	}
+}
+
+func TestRunner_Normalize(t *testing.T) {
+	dict := miniDict()
+	runner := dict.Runner()
+	runner.Normalize = func(text string) string {
+		return NormalizeText(text) + "tok"
+	}
+
+	assert.Equal(t, "392 <äp>", runner.Run("fmäpe")[0].String())
+}
+
+func TestDictionary_Lookup_FoldedInfixes(t *testing.T) {
+	grammar, err := ParseGrammar(strings.NewReader(`
+// Made-up infixes that only go before or not before "ä"
+infixes 2
+	""
+	olo >ä
+	ula -ä
+`))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	dict := &Dictionary{FoldDiacritics: true}
+	dict.ApplyGrammar(grammar)
+	dict.Insert(*ParseEntry("-1:k<0><1>e<2>än:vtr."))
+
+	table := []struct {
+		Lookup   string
+		Expected string
+		Folded   bool
+	}{
+		{"keoloän", "-1 <olo>", false},
+		{"keoloan", "-1 <olo>", true},
+		{"keulaän", "", false},
+		{"keulaan", "", false},
+	}
+
+	for _, row := range table {
+		t.Run(row.Lookup, func(t *testing.T) {
+			resStr := make([]string, 0, 1)
+			for _, res := range dict.Lookup(row.Lookup) {
+				resStr = append(resStr, res.String())
+				assert.Equal(t, row.Folded, res.Folded)
+			}
+
+			assert.Equal(t, row.Expected, strings.Join(resStr, ";"))
+		})
+	}
+}
@@ -5,6 +5,22 @@ import (
 )
 
 func NounFromEntry(entry Entry) *Node {
+	res := nounFromEntry(entry)
+	if entry.HasFlag("noplural") {
+		renameSubTrees(res, "np", "np_sg")
+	}
+
+	// The derivations are only for common nouns made of one word.
+	word := strings.ToLower(entry.Word)
+	if entry.HasPoS("n.") && !entry.HasPoS("inter.") && !entry.HasFlag("noderiv") && !strings.Contains(word, " ") {
+		res.MergeFrom(*GenerateNounNga(word).AndThenResult(derivedResultValue(entry.ID, "adj.", "n.", DerivationNgaAdjective)))
+		res.MergeFrom(*GenerateNounLe(word).AndThenResult(derivedResultValue(entry.ID, "adj.", "n.", DerivationLeAdjective)))
+	}
+
+	return res
+}
+
+func nounFromEntry(entry Entry) *Node {
 	resultValue := entry.ID
 	if len(entry.PoS) > 1 {
 		for _, pos := range entry.PoS {
@@ -15,67 +31,130 @@ func NounFromEntry(entry Entry) *Node {
 	}
 
 	word := strings.ToLower(entry.Word)
-	if entry.HasFlag("loanword") && strings.HasSuffix(word, "ì") {
+
+	generate := GenerateNoun
+	if entry.HasFlag("nocase") {
+		generate = GenerateCaselessNoun
+	}
+
+	// Loanwords drop the last ì before case endings, but not if it's stressed.
+	finalStressed, _ := entry.IsStressedAt(len(word) - 1)
+	if entry.HasFlag("loanword") && !entry.HasFlag("nocase") && strings.HasSuffix(word, "ì") && !finalStressed {
 		return CombineTrees(
 			BuildTree("$np", word).AndThenResult(resultValue),
 			GenerateNoun(ParseNode(strings.TrimSuffix(word, "ì"))).AndThenResult(resultValue),
 		)
 	} else if strings.Contains(word, " ") {
-		// This is pretty much just drawing boundaries based on known words.
-		// As of November 2024, they are the following words
-		//  toruk makto*         tsko swizaw*
-		//  eltu* lefngap        pängkxoyu* lekoren
-		//  tìftia* kifkeyä      uvan* letokx
-		//  rel* arusikx         tìftiatu* kifkeyä
-		//  swoasey* ayll        yomyo* lerìk
-		//  mo* a fngä'          pamrelvul* lerìn
-		//  koren* ayll          tslikxyu* latopin
-		//  tslikxyu* tsawlak    renu* ngampamä
-		//  txawnulsrung* a yur  txawnulsrung* a tswayon
-		//  trrpxì* Sawtuteyä    mo* letrrtrr
-		//  mo* a yom            mo* a hahaw
-		//  (* marks where suffixes shall go)
-
-		split := strings.Split(word, " ")
-		if len(split) > 2 { // txawnulsrung a tswayon, mo a hahaw, etc...
-			preWord := split[0]
-			postWord := word[len(split[0]):]
-
-			return GenerateNoun(ParseNode(preWord)).AndThen(ParseNode(postWord)).AndThenResult(resultValue)
-		} else {
-			leftWord := split[0]
-			rightWord := split[1]
-
-			if strings.HasSuffix(leftWord, "yu") ||
-				strings.HasSuffix(leftWord, "tu") ||
-				strings.HasPrefix(rightWord, "a") ||
-				strings.HasPrefix(rightWord, "le") ||
-				strings.HasSuffix(rightWord, "yä") ||
-				strings.HasSuffix(rightWord, "ä") {
-				return GenerateNoun(ParseNode(leftWord)).
-					AndThen(ParseNode(" ")).
-					AndThen(ParseNode(rightWord)).
-					AndThenResult(resultValue)
-			} else {
-				return BuildTree(leftWord).
-					AndThen(ParseNode(" ")).
-					AndThen(*GenerateNoun(ParseNode(rightWord))).
-					AndThenResult(resultValue)
-			}
+		words := strings.Split(word, " ")
+
+		head := guessNounHead(words)
+		if entry.HeadWord != nil && *entry.HeadWord < len(words) {
+			head = *entry.HeadWord
 		}
+
+		return generateMultiWordNoun(words, head, generate).AndThenResult(resultValue)
 	} else {
 		if entry.HasPoS("inter.") {
 			return GenerateUnPrefixedNoun(ParseNode(word)).AndThenResult(resultValue)
 		} else {
-			return GenerateNoun(ParseNode(word)).AndThenResult(resultValue)
+			return generate(ParseNode(word)).AndThenResult(resultValue)
 		}
 	}
 }
 
+// GenerateNounNga generates the -nga' adjective for what contains the noun (e.g. txepnga').
+func GenerateNounNga(word string) *Node {
+	return GenerateAdjective(*BuildTree(word, "-nga'"))
+}
+
+// GenerateNounLe generates the le- adjective for what has the quality of the noun (e.g. lefngap).
+func GenerateNounLe(word string) *Node {
+	return GenerateAdjective(*BuildTree("le-", word))
+}
+
+// GenerateMultiWordNoun generates a noun where only the word at the head index takes the affixes.
+func GenerateMultiWordNoun(words []string, head int) *Node {
+	return generateMultiWordNoun(words, head, GenerateNoun)
+}
+
+func generateMultiWordNoun(words []string, head int, generate func(core Node) *Node) *Node {
+	res := EmptyTree()
+	if head > 0 {
+		res = BuildTree(strings.Join(words[:head], " "), " ")
+	}
+
+	res.AndThen(*generate(ParseNode(words[head])))
+	if head < len(words)-1 {
+		res.AndThen(ParseNode(" " + strings.Join(words[head+1:], " ")))
+	}
+
+	return res
+}
+
+// guessNounHead finds the word that takes the affixes in a multi-word noun without a marked head. This
+// is pretty much just drawing boundaries based on known words.
+//
+// As of November 2024, they are the following words
+//
+//	toruk makto*         tsko swizaw*
+//	eltu* lefngap        pängkxoyu* lekoren
+//	tìftia* kifkeyä      uvan* letokx
+//	rel* arusikx         tìftiatu* kifkeyä
+//	swoasey* ayll        yomyo* lerìk
+//	mo* a fngä'          pamrelvul* lerìn
+//	koren* ayll          tslikxyu* latopin
+//	tslikxyu* tsawlak    renu* ngampamä
+//	txawnulsrung* a yur  txawnulsrung* a tswayon
+//	trrpxì* Sawtuteyä    mo* letrrtrr
+//	mo* a yom            mo* a hahaw
+//	(* marks where suffixes shall go)
+//
+// Mark the head in the entry instead (see Entry.HeadWord) for anything that does not fit.
+func guessNounHead(words []string) int {
+	if len(words) > 2 { // txawnulsrung a tswayon, mo a hahaw, etc...
+		return 0
+	}
+
+	leftWord := words[0]
+	rightWord := words[1]
+	if strings.HasSuffix(leftWord, "yu") ||
+		strings.HasSuffix(leftWord, "tu") ||
+		strings.HasPrefix(rightWord, "a") ||
+		strings.HasPrefix(rightWord, "le") ||
+		strings.HasSuffix(rightWord, "yä") ||
+		strings.HasSuffix(rightWord, "ä") {
+		return 0
+	}
+
+	return 1
+}
+
+// GenerateNoun generates a noun with all the prefixes and suffixes. The runner drops the results with an
+// adposition prefix and other suffixes than -to and -sì, see Runner.Adpositions.
 func GenerateNoun(core Node) *Node {
 	return generateNoun(*BuildTree("$np").AndThen(core))
 }
 
+// GenerateCaselessNoun generates a noun that takes the prefixes, but no case endings or other suffixes
+// than -sì.
+func GenerateCaselessNoun(core Node) *Node {
+	return BuildTree("$np").AndThen(core).AndThen(*CombineTrees(
+		BuildTree("/hook"),
+		BuildTree("-sì"),
+	))
+}
+
+// renameSubTrees makes the subtree nodes with the name use another subtree instead.
+func renameSubTrees(tree *Node, from, to string) {
+	tree.SearchReplace(func(node *Node) *Node {
+		if node.Kind == NKSubTree && node.Value == from {
+			node.Value = to
+		}
+
+		return nil
+	})
+}
+
 func GenerateUnPrefixedNoun(core Node) *Node {
 	return generateNoun(*EmptyTree().AndThen(core))
 }
@@ -106,6 +185,7 @@ func generateNounTail(tail string) *Node {
 
 	res.MergeFrom(*BuildTree(tail, findNounSuffix(tail)))
 
+	// These go for every noun that ends the same way, so they're rules here rather than irr: flags.
 	if !strings.HasPrefix(tail, "-") {
 		// Edge case: soaia -> soaiä
 		if strings.HasSuffix(tail, "ia") {
@@ -26,6 +26,34 @@ func TestNounFromEntry(t *testing.T) {
 			Entry: "604:ikran:n.", Test: "ikranti",
 			Expected: "604 -ti",
 		},
+		{
+			Entry: "604:ikran:n.", Test: "ikrannga'",
+			Expected: "604:adj. -nga'",
+		},
+		{
+			Entry: "604:ikran:n.", Test: "aikrannga'",
+			Expected: "604:adj. a- -nga'",
+		},
+		{
+			Entry: "604:ikran:n.", Test: "leikrana",
+			Expected: "604:adj. le- -a",
+		},
+		{
+			Entry: "604:ikran:n.", Test: "leikranti",
+			Expected: "",
+		},
+		{
+			Entry: "5476:hametsì:n.:loanword", Test: "hametsìnga'",
+			Expected: "5476:adj. -nga'",
+		},
+		{
+			Entry: "616:irayo:n.,intj.", Test: "leirayo",
+			Expected: "616:adj. le-",
+		},
+		{
+			Entry: "1524:pesu:inter.:inter:n.", Test: "pesunga'",
+			Expected: "",
+		},
 		{
 			Entry: "616:irayo:n.,intj.", Test: "irayoru",
 			Expected: "616:n. -ru",
@@ -46,10 +74,34 @@ func TestNounFromEntry(t *testing.T) {
 			Entry: "5476:hametsì:n.:loanword", Test: "hametìti",
 			Expected: "",
 		},
+		{
+			Entry: "-5476:ha.me.ˈtsì:n.:loanword", Test: "hametsìru", // Fake entry, the stressed ì is kept.
+			Expected: "-5476 -ru",
+		},
+		{
+			Entry: "-5476:ha.me.ˈtsì:n.:loanword", Test: "hametsur",
+			Expected: "",
+		},
 		{
 			Entry: "13492:txawnulsrung a tswayon:n.", Test: "txawnulsrung a tswayon",
 			Expected: "13492",
 		},
+		{
+			Entry: "-1:ikran* txe'lan:n.", Test: "ikranìl txe'lan", // Fake entry where the guess would be wrong.
+			Expected: "-1 -ìl",
+		},
+		{
+			Entry: "-1:ikran* txe'lan:n.", Test: "ikran txe'lanìl",
+			Expected: "",
+		},
+		{
+			Entry: "-1:ikran txe'lan:n.", Test: "ikran txe'lanìl",
+			Expected: "-1 -ìl",
+		},
+		{
+			Entry: "-2:mo a ikran* txe'lan:n.", Test: "mo a ayikran txe'lan",
+			Expected: "-2 ay-",
+		},
 		{
 			Entry: "13492:txawnulsrung a tswayon:n.", Test: "txawnulsrungit a tswayon",
 			Expected: "13492 -it",
@@ -199,3 +251,21 @@ func TestGenerateNoun(t *testing.T) {
 		})
 	}
 }
+
+// TestGenerateNoun_Size checks that the adposition prefixes do not add to the noun trees, which are the
+// same size as before they were added.
+func TestGenerateNoun_Size(t *testing.T) {
+	assert.Equal(t, 32, NounFromEntry(*ParseEntry("2140:tìfmetok:n.")).Size())
+
+	dict := &Dictionary{}
+	dict.Insert(*ParseEntry("-1:fpi+:adp."))
+	size := dict.Root.Size()
+	dict.Insert(*ParseEntry("2140:tìfmetok:n."))
+	withAdposition := dict.Root.Size() - size
+
+	dict = &Dictionary{}
+	dict.Insert(*ParseEntry("-1:fpi:intj."))
+	size = dict.Root.Size()
+	dict.Insert(*ParseEntry("2140:tìfmetok:n."))
+	assert.Equal(t, dict.Root.Size()-size, withAdposition)
+}
@@ -0,0 +1,131 @@
+package lutral
+
+import "strings"
+
+// NumeralID is the ID of the results for numerals that are not listed as entries, e.g. mrrvomun. Their
+// PoS is "num.", and the value is in Result.Number. The numerals that are listed get the entry's ID instead.
+const NumeralID = "#"
+
+// numeralTree is the tree that the dictionary's runners look up the numerals in, see Runner.NumeralRoot.
+var numeralTree = NumeralTree()
+
+// NumeralTree generates the tree for the octal numerals, both cardinal and ordinal. They are matched by
+// NKNumeral nodes rather than listed, so that any compound like mrrvomun can be found. Dictionary does not
+// put it in its Root, but gives it to its runners as the NumeralRoot.
+func NumeralTree() *Node {
+	return CombineTrees(
+		GenerateAdjective(*BuildTree("#")).AndThenResult(NumeralID+":num."),
+		GenerateAdjective(*BuildTree("#ve")).AndThenResult(NumeralID+":num."),
+	)
+}
+
+// ParseNumeral parses a whole numeral word (e.g. "mrrvomun" or "muve") and returns its value.
+func ParseNumeral(word string) (value int, ordinal bool, ok bool) {
+	for _, ordinal := range []bool{false, true} {
+		for _, match := range numeralPrefixes(word, ordinal, false, nil) {
+			if match.Length == len(word) {
+				return match.Value, ordinal, true
+			}
+		}
+	}
+
+	return 0, false, false
+}
+
+type numeralMatch struct {
+	Value  int
+	Length int
+	// Folded is set if it only matched with folded letters (e.g. kina for kinä).
+	Folded bool
+}
+
+type numeralWord struct {
+	Text  string
+	Value int
+}
+
+// numeralPrefixes finds all the numerals that the text starts with. The places must come from the
+// highest to the lowest, and they can only be followed by the suffix form of the units (e.g. vol+aw).
+// If fold is set, the letters can also be without their diacritics, as with Runner.FoldDiacritics.
+func numeralPrefixes(text string, ordinal, fold bool, buf []numeralMatch) []numeralMatch {
+	ending := ""
+	if ordinal {
+		ending = "ve"
+	}
+
+	if !ordinal && strings.HasPrefix(text, "kew") {
+		buf = append(buf, numeralMatch{Value: 0, Length: len("kew")})
+	}
+
+	units := numeralUnits
+	if ordinal {
+		units = numeralOrdinalUnits
+	}
+	for _, unit := range units {
+		if after, ok, folded := cutFoldedPrefix(text, unit.Text, fold); ok {
+			buf = append(buf, numeralMatch{Value: unit.Value, Length: len(text) - len(after), Folded: folded})
+		}
+	}
+
+	return numeralPlacePrefixes(text, 0, 0, 0, ending, fold, false, buf)
+}
+
+func numeralPlacePrefixes(text string, offset, value, firstPlace int, ending string, fold, folded bool, buf []numeralMatch) []numeralMatch {
+	for i := firstPlace; i < len(numeralPlaces); i++ {
+		place := numeralPlaces[i]
+
+		for _, multiplier := range numeralMultipliers {
+			afterMultiplier, ok, foldedMultiplier := cutFoldedPrefix(text[offset:], multiplier.Text, fold)
+			if !ok {
+				continue
+			}
+
+			placeText := place.Text
+			afterPlace, ok, foldedPlace := cutFoldedPrefix(afterMultiplier, placeText, fold)
+			if !ok {
+				// vol becomes vo before consonants (mrrvomun), but vol is also accepted (zamvolmun).
+				if place.Text != "vol" {
+					continue
+				}
+				if afterPlace, ok, foldedPlace = cutFoldedPrefix(afterMultiplier, "vo", fold); !ok {
+					continue
+				}
+				placeText = "vo"
+			}
+
+			nextOffset := len(text) - len(afterPlace)
+			nextValue := value + multiplier.Value*place.Value
+			nextFolded := folded || foldedMultiplier || foldedPlace
+
+			if placeText != "vo" && strings.HasPrefix(text[nextOffset:], ending) {
+				buf = append(buf, numeralMatch{Value: nextValue, Length: nextOffset + len(ending), Folded: nextFolded})
+			}
+
+			for _, unit := range numeralUnitSuffixes {
+				if placeText == "vo" && unit.Text == "aw" {
+					continue
+				}
+				if afterUnit, ok, foldedUnit := cutFoldedPrefix(text[nextOffset:], unit.Text+ending, fold); ok {
+					buf = append(buf, numeralMatch{Value: nextValue + unit.Value, Length: len(text) - len(afterUnit), Folded: nextFolded || foldedUnit})
+				}
+			}
+
+			if placeText != "vo" {
+				buf = numeralPlacePrefixes(text, nextOffset, nextValue, i+1, ending, fold, nextFolded, buf)
+			}
+		}
+	}
+
+	return buf
+}
+
+// numeralPlaces must be ordered from highest to lowest.
+var numeralPlaces = []numeralWord{{"zazam", 4096}, {"vozam", 512}, {"zam", 64}, {"vol", 8}}
+
+var numeralMultipliers = []numeralWord{{"", 1}, {"me", 2}, {"pxe", 3}, {"tsì", 4}, {"mrr", 5}, {"pu", 6}, {"ki", 7}}
+
+var numeralUnits = []numeralWord{{"'aw", 1}, {"mune", 2}, {"pxey", 3}, {"tsìng", 4}, {"mrr", 5}, {"pukap", 6}, {"kinä", 7}}
+
+var numeralOrdinalUnits = []numeralWord{{"'awve", 1}, {"muve", 2}, {"pxeyve", 3}, {"tsìve", 4}, {"mrrve", 5}, {"puve", 6}, {"kive", 7}}
+
+var numeralUnitSuffixes = []numeralWord{{"aw", 1}, {"mun", 2}, {"pey", 3}, {"sìng", 4}, {"mrr", 5}, {"fu", 6}, {"hin", 7}}
@@ -0,0 +1,120 @@
+package lutral
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseNumeral(t *testing.T) {
+	table := []struct {
+		Word    string
+		Value   int
+		Ordinal bool
+		OK      bool
+	}{
+		{"kew", 0, false, true},
+		{"'aw", 1, false, true},
+		{"kinä", 7, false, true},
+		{"vol", 8, false, true},
+		{"volaw", 9, false, true},
+		{"mevol", 16, false, true},
+		{"mrrvomun", 42, false, true},
+		{"kivohin", 63, false, true},
+		{"zam", 64, false, true},
+		{"zamvomun", 74, false, true},
+		{"zamvolmun", 74, false, true},
+		{"mezamtsìvol", 160, false, true},
+		{"vozam", 512, false, true},
+		{"zazam", 4096, false, true},
+		{"mezazampuvozamkizamvolaw", 8192 + 6*512 + 7*64 + 8 + 1, false, true},
+		{"muve", 2, true, true},
+		{"tsìve", 4, true, true},
+		{"volve", 8, true, true},
+		{"mevolawve", 17, true, true},
+		{"zamve", 64, true, true},
+		{"vomun", 10, false, true},
+		{"voaw", 0, false, false},
+		{"mrrvo", 0, false, false},
+		{"volzam", 0, false, false},
+		{"mune'aw", 0, false, false},
+		{"muneve", 0, false, false},
+	}
+
+	for _, row := range table {
+		t.Run(row.Word, func(t *testing.T) {
+			value, ordinal, ok := ParseNumeral(row.Word)
+			assert.Equal(t, row.OK, ok)
+			assert.Equal(t, row.Value, value)
+			assert.Equal(t, row.Ordinal, ordinal)
+		})
+	}
+}
+
+func TestNumeralTree(t *testing.T) {
+	table := []struct {
+		Test     string
+		Expected string
+	}{
+		{"pxey", "#:num. (3)"},
+		{"pxeysì", "#:num. (3) -sì"},
+		{"apxevopey", "#:num. (27) a-"},
+		{"pxeyvea", "#:num. (3) -ve-a"},
+		{"volhin", "#:num. (15)"},
+		{"pxeyvol", ""},
+	}
+
+	for _, row := range table {
+		t.Run(row.Test, func(t *testing.T) {
+			runner := Runner{Root: NumeralTree()}
+
+			resStr := ""
+			for _, res := range runner.Run(row.Test) {
+				if len(resStr) > 0 {
+					resStr += ";"
+				}
+				resStr += res.String()
+			}
+
+			assert.Equal(t, row.Expected, resStr)
+		})
+	}
+}
+
+func TestDictionary_Lookup_Numerals(t *testing.T) {
+	dict := &Dictionary{}
+	lookup := func(word string) string {
+		resStr := ""
+		for _, res := range dict.Lookup(word) {
+			if len(resStr) > 0 {
+				resStr += ";"
+			}
+			resStr += res.String()
+		}
+
+		return resStr
+	}
+
+	// The numerals are found before any are listed.
+	dict.Insert(*ParseEntry("800:kifkey:n."))
+	assert.Equal(t, "#:num. (42)", lookup("mrrvomun"))
+	assert.Equal(t, "#:num. (2)", lookup("mune"))
+
+	dict.Insert(*ParseEntry("1176:mune:num."))
+	assert.Equal(t, "#:num. (42)", lookup("mrrvomun"))
+	assert.Equal(t, "1176 (2)", lookup("mune"))
+	assert.Equal(t, NumeralID, dict.Lookup("amrrvomun")[0].ID)
+
+	// A dictionary loaded from JSON finds them as well, since they are not in the Root.
+	data, err := json.Marshal(dict)
+	assert.NoError(t, err)
+	dict = &Dictionary{}
+	assert.NoError(t, json.Unmarshal(data, dict))
+	assert.Equal(t, "#:num. (42)", lookup("mrrvomun"))
+	assert.Equal(t, "1176 (2)", lookup("mune"))
+
+	// So does one with a Root of its own.
+	dict = &Dictionary{Root: *BuildTree("kifkey", "=800")}
+	assert.Equal(t, "#:num. (42)", lookup("mrrvomun"))
+	assert.Equal(t, "800", lookup("kifkey"))
+}
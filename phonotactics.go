@@ -0,0 +1,173 @@
+package lutral
+
+import (
+	"slices"
+	"strings"
+)
+
+// Syllabify splits the word into syllables, e.g. "tìsyìmawnun'i" becomes "tì", "syì", "maw", "nun" and
+// "'i". The consonants between two vowels go to the next syllable as long as they can start one. It
+// returns a *PhonotacticError if the word is not a valid Na'vi word.
+func Syllabify(word string) ([]string, error) {
+	word = strings.ToLower(word)
+
+	phonemes, err := splitPhonemes(word)
+	if err != nil {
+		return nil, err
+	}
+
+	var nuclei []int
+	for i, phoneme := range phonemes {
+		if phoneme.Kind != phonemeConsonant {
+			nuclei = append(nuclei, i)
+		}
+	}
+	if len(nuclei) == 0 {
+		return nil, &PhonotacticError{Word: word, Offset: 0, Err: ErrNoVowel}
+	}
+
+	// The syllables start where their onset starts.
+	starts := []int{0}
+	if onset := phonemes[:nuclei[0]]; !isValidOnset(onset) {
+		return nil, &PhonotacticError{Word: word, Offset: 0, Err: ErrIllegalOnset}
+	}
+	for i := 1; i < len(nuclei); i++ {
+		prev := phonemes[nuclei[i-1]]
+		cluster := phonemes[nuclei[i-1]+1 : nuclei[i]]
+
+		split := -1
+		for coda := 0; coda <= len(cluster); coda++ {
+			if isValidCoda(prev, cluster[:coda]) && isValidOnset(cluster[coda:]) {
+				split = nuclei[i-1] + 1 + coda
+				break
+			}
+		}
+		if split == -1 {
+			return nil, &PhonotacticError{Word: word, Offset: prev.Offset + len(prev.Text), Err: ErrIllegalCluster}
+		}
+
+		starts = append(starts, split)
+	}
+	last := phonemes[nuclei[len(nuclei)-1]]
+	if !isValidCoda(last, phonemes[nuclei[len(nuclei)-1]+1:]) {
+		return nil, &PhonotacticError{Word: word, Offset: last.Offset + len(last.Text), Err: ErrIllegalCoda}
+	}
+
+	res := make([]string, 0, len(starts))
+	for i, start := range starts {
+		end := len(word)
+		if i+1 < len(starts) {
+			end = phonemes[starts[i+1]].Offset
+		}
+
+		res = append(res, word[phonemes[start].Offset:end])
+	}
+
+	return res, nil
+}
+
+// ValidateWord checks if the word follows the rules for what a Na'vi word can look like. The error is a
+// *PhonotacticError telling where it breaks them.
+func ValidateWord(word string) error {
+	_, err := Syllabify(word)
+	return err
+}
+
+type phonemeKind int
+
+const (
+	phonemeConsonant phonemeKind = iota
+	phonemeVowel
+	phonemePseudovowel
+)
+
+type phoneme struct {
+	Text   string
+	Offset int
+	Kind   phonemeKind
+}
+
+// splitPhonemes splits the lowercase word into its letters. The rr and ll are pseudovowels after a
+// consonant, otherwise they're two consonants.
+func splitPhonemes(word string) ([]phoneme, error) {
+	res := make([]phoneme, 0, len(word))
+
+	for offset := 0; offset < len(word); {
+		text, kind := "", phonemeConsonant
+		for _, digraph := range digraphs {
+			if strings.HasPrefix(word[offset:], digraph) {
+				text = digraph
+				break
+			}
+		}
+
+		if text == "rr" || text == "ll" {
+			if len(res) > 0 && res[len(res)-1].Kind == phonemeConsonant {
+				kind = phonemePseudovowel
+			} else {
+				text = text[:1]
+			}
+		}
+
+		if text == "" {
+			for _, ch := range word[offset:] {
+				text = string(ch)
+				break
+			}
+
+			switch {
+			case strings.Contains(phonemeVowels, text):
+				kind = phonemeVowel
+			case !strings.Contains(phonemeConsonants, text):
+				return nil, &PhonotacticError{Word: word, Offset: offset, Err: ErrUnknownLetter}
+			}
+		}
+
+		res = append(res, phoneme{Text: text, Offset: offset, Kind: kind})
+		offset += len(text)
+	}
+
+	return res, nil
+}
+
+// isValidOnset checks if the consonants can start a syllable: none, one, or f, s or ts followed by one
+// of the clusterables.
+func isValidOnset(onset []phoneme) bool {
+	switch len(onset) {
+	case 0, 1:
+		return true
+	case 2:
+		return (onset[0].Text == "f" || onset[0].Text == "s" || onset[0].Text == "ts") &&
+			slices.Contains(clusterables, onset[1].Text)
+	}
+
+	return false
+}
+
+// isValidCoda checks if the consonants can end a syllable with the nucleus. A pseudovowel takes no coda,
+// and the w or y of the diphthongs can be followed by one more consonant.
+func isValidCoda(nucleus phoneme, coda []phoneme) bool {
+	if len(coda) > 0 && nucleus.Kind == phonemePseudovowel {
+		return false
+	}
+
+	if len(coda) > 0 && (nucleus.Text == "a" || nucleus.Text == "e") && (coda[0].Text == "w" || coda[0].Text == "y") {
+		coda = coda[1:]
+	}
+
+	switch len(coda) {
+	case 0:
+		return true
+	case 1:
+		return slices.Contains(codaConsonants, coda[0].Text)
+	}
+
+	return false
+}
+
+var digraphs = []string{"px", "tx", "kx", "ts", "ng", "rr", "ll"}
+
+const phonemeVowels = "aäeéiìouù"
+const phonemeConsonants = "ptk'fshvzmnrlwy"
+
+var codaConsonants = []string{"px", "tx", "kx", "p", "t", "k", "'", "m", "n", "ng", "r", "l"}
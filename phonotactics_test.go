@@ -0,0 +1,80 @@
+package lutral
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyllabify(t *testing.T) {
+	table := []struct {
+		Word     string
+		Expected string
+	}{
+		{"tìsyìmawnun'i", "tì.syì.maw.nun.'i"},
+		{"skxawng", "skxawng"},
+		{"eltu", "el.tu"},
+		{"frrfen", "frr.fen"},
+		{"kllte", "kll.te"},
+		{"ayoeng", "a.yo.eng"},
+		{"tsawke", "tsaw.ke"},
+		{"kxetse", "kxe.tse"},
+		{"'awkx", "'awkx"},
+		{"txawnulsrung", "txaw.nul.srung"},
+		{"fmetok", "fme.tok"},
+		{"soaia", "so.a.i.a"},
+		{"Kaltxì", "kal.txì"},
+		{"ngä'än", "ngä.'än"},
+		{"hametsì", "ha.me.tsì"},
+		{"kelku", "kel.ku"},
+		{"tsyal", "tsyal"},
+		{"tskxe", "tskxe"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Word, func(t *testing.T) {
+			res, err := Syllabify(row.Word)
+			assert.NoError(t, err)
+			assert.Equal(t, row.Expected, strings.Join(res, "."))
+		})
+	}
+}
+
+func TestValidateWord(t *testing.T) {
+	table := []struct {
+		Word   string
+		Err    error
+		Offset int
+	}{
+		{"kaltxì", nil, 0},
+		{"tìsyìmawnun'i", nil, 0},
+		{"glurb", ErrUnknownLetter, 0},
+		{"kaltxì ma", ErrUnknownLetter, 7},
+		{"txkx", ErrNoVowel, 0},
+		{"", ErrNoVowel, 0},
+		{"mskawng", ErrIllegalOnset, 0},
+		{"mtsa", ErrIllegalOnset, 0},
+		{"kats", ErrIllegalCoda, 2},
+		{"kawnt", ErrIllegalCoda, 2},
+		{"kllt", ErrIllegalCoda, 3},
+		{"kamptsa", ErrIllegalCluster, 2},
+		{"ayfmtok", ErrIllegalCluster, 1},
+	}
+
+	for _, row := range table {
+		t.Run(row.Word, func(t *testing.T) {
+			err := ValidateWord(row.Word)
+			if row.Err == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, row.Err)
+			var phonotacticErr *PhonotacticError
+			if assert.ErrorAs(t, err, &phonotacticErr) {
+				assert.Equal(t, row.Offset, phonotacticErr.Offset)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package lutral
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+)
+
+// phraseMatch is where a phrase was found by matchPhrases.
+type phraseMatch struct {
+	ID    string
+	Words []phraseWord
+}
+
+// phraseWord is a word of a phraseMatch. The index is -1 for an unknown word in an {any} slot.
+type phraseWord struct {
+	Template Result
+	Position int
+	Index    int
+}
+
+// The words of a phrase entry that are not looked up. A slot is "{" + the parts of speech without the
+// trailing dot separated by "|" + "}", e.g. "{n|pn}". "{any}" is any word, and a case (see Case) can
+// follow after a "/", e.g. "{n/agentive}". A gap is up to maxPhraseGap words in the same sentence.
+const (
+	phraseAny = "{any}"
+	phraseGap = "{gap}"
+)
+
+// maxPhraseGap is the most words a {gap} can take.
+const maxPhraseGap = 6
+
+// matchPhrases replaces the words that make up a phrase with the phrase's result, for every phrase found in
+// the results of extract. Where phrases overlap, the one with the most words wins, then the one that starts
+// first, then the one with the lowest ID. The words in a gap are kept as they are. The spans are the ones of
+// each position in the original text.
+func (runner *Runner) matchPhrases(original string, spans [][2]int) {
+	// ranges has the results at each position as offsets in runner.res.
+	ranges := make([][2]int, len(spans)+1)
+	for i, res := range runner.res {
+		if ranges[res.Position][1] == 0 {
+			ranges[res.Position][0] = i
+		}
+		ranges[res.Position][1] = i + 1
+	}
+
+	// sentenceEnds tells if a sentence ends after each position, since gaps do not go past that.
+	sentenceEnds := make([]bool, len(ranges))
+	for i := 0; i+1 < len(spans); i++ {
+		sentenceEnds[i+1] = strings.ContainsAny(original[spans[i][1]:spans[i+1][0]], sentenceTerminators)
+	}
+
+	phraseIDs := make([]string, 0, len(runner.PhraseMap))
+	for phraseID := range runner.PhraseMap {
+		phraseIDs = append(phraseIDs, phraseID)
+	}
+	slices.Sort(phraseIDs)
+
+	var matches []phraseMatch
+	for _, phraseID := range phraseIDs {
+		phrase := runner.PhraseMap[phraseID]
+		for position := 1; position < len(ranges); position++ {
+			if words, ok := runner.matchPhraseAt(phrase, position, ranges, sentenceEnds, nil); ok && len(words) > 0 && words[0].Position == position {
+				matches = append(matches, phraseMatch{ID: phraseID, Words: words})
+			}
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+
+	slices.SortStableFunc(matches, func(a, b phraseMatch) int {
+		return cmp.Or(
+			cmp.Compare(len(b.Words), len(a.Words)),
+			cmp.Compare(a.Words[0].Position, b.Words[0].Position),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	covered := make([]bool, len(ranges))
+	accepted := make(map[int]phraseMatch, len(matches))
+	for _, match := range matches {
+		free := true
+		for _, word := range match.Words {
+			free = free && !covered[word.Position]
+		}
+		if !free {
+			continue
+		}
+
+		for _, word := range match.Words {
+			covered[word.Position] = true
+		}
+		accepted[match.Words[0].Position] = match
+	}
+
+	res := make([]Result, 0, len(runner.res))
+	for position := 1; position < len(ranges); position++ {
+		match, ok := accepted[position]
+		if !ok {
+			if !covered[position] {
+				res = append(res, runner.res[ranges[position][0]:ranges[position][1]]...)
+			}
+
+			continue
+		}
+
+		phraseResult := Result{ID: match.ID, Position: position, Spelling: SpellingStandard}
+		for _, word := range match.Words {
+			if isPhraseSlot(word.Template.ID) {
+				filler := Result{Position: word.Position, Form: original[spans[word.Position-1][0]:spans[word.Position-1][1]]}
+				if word.Index != -1 {
+					filler = runner.res[word.Index]
+				}
+
+				phraseResult.Slots = append(phraseResult.Slots, filler)
+				continue
+			}
+
+			phraseResult.AddAffixesFrom(runner.res[word.Index], word.Template)
+			if runner.res[word.Index].Spelling != SpellingStandard {
+				phraseResult.Spelling = runner.res[word.Index].Spelling
+			}
+		}
+
+		last := match.Words[len(match.Words)-1].Position
+		phraseResult.Form = original[spans[position-1][0]:spans[last-1][1]]
+
+		res = append(res, phraseResult)
+	}
+
+	runner.res = res
+}
+
+// matchPhraseAt matches the phrase from the position, adding the words to the list. The gaps take as few
+// words as they can, and they stop where a sentence ends.
+func (runner *Runner) matchPhraseAt(phrase []Result, position int, ranges [][2]int, sentenceEnds []bool, words []phraseWord) ([]phraseWord, bool) {
+	if len(phrase) == 0 {
+		return words, true
+	}
+
+	if isPhraseGap(phrase[0].ID) {
+		for next := position; next <= len(ranges) && next-position <= maxPhraseGap; next++ {
+			if (len(words) > 0 || next > position) && sentenceEnds[next-1] {
+				break
+			}
+
+			if res, ok := runner.matchPhraseAt(phrase[1:], next, ranges, sentenceEnds, words); ok {
+				return res, true
+			}
+		}
+
+		return nil, false
+	}
+
+	if position >= len(ranges) {
+		return nil, false
+	}
+
+	index, ok := runner.matchPhraseWord(phrase[0], ranges[position])
+	if !ok {
+		return nil, false
+	}
+
+	return runner.matchPhraseAt(phrase[1:], position+1, ranges, sentenceEnds, append(words, phraseWord{
+		Template: phrase[0],
+		Position: position,
+		Index:    index,
+	}))
+}
+
+// matchPhraseWord finds the first result in the range that the phrase's word covers, or that fits in the slot.
+func (runner *Runner) matchPhraseWord(template Result, resRange [2]int) (int, bool) {
+	if resRange[0] == resRange[1] {
+		return -1, template.ID == phraseAny
+	}
+
+	for i := resRange[0]; i < resRange[1]; i++ {
+		if isPhraseSlot(template.ID) {
+			if runner.fitsPhraseSlot(runner.res[i], template.ID) {
+				return i, true
+			}
+		} else if runner.res[i].CoveredBy(template) {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+// fitsPhraseSlot checks if the result has one of the slot's parts of speech and its case.
+func (runner *Runner) fitsPhraseSlot(result Result, slot string) bool {
+	types, slotCase, _ := strings.Cut(strings.Trim(slot, "{}"), "/")
+	if slotCase != "" && result.Features().Case != Case(slotCase) {
+		return false
+	}
+	if types == "any" {
+		return true
+	}
+
+	for _, pos := range strings.Split(types, "|") {
+		if runner.hasPoS(result, pos+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasPoS checks if the result is one of the parts of speech. A result without a PoS has the ones of its entry.
+func (runner *Runner) hasPoS(result Result, pos ...string) bool {
+	if result.PoS != "" {
+		return slices.Contains(pos, result.PoS)
+	}
+
+	for _, entryPoS := range runner.LemmaMap[result.ID].PoS {
+		if slices.Contains(pos, entryPoS) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isPhraseSlot(word string) bool {
+	return strings.HasPrefix(word, "{") && !isPhraseGap(word)
+}
+
+func isPhraseGap(word string) bool {
+	return word == phraseGap
+}
+
+// phraseTemplates finds the results for the words of a phrase entry, like simplestResultSet. The slots and
+// gaps are kept as results with them as the ID. It returns nil if a word is not found.
+func (dictionary *Dictionary) phraseTemplates(word string) ([]Result, error) {
+	runner := dictionary.Runner()
+	runner.PhraseMap = nil
+	runner.FoldDiacritics = false
+
+	var res []Result
+	var run []string
+	flush := func() error {
+		if len(run) == 0 {
+			return nil
+		}
+
+		entries, err := runner.extract(context.Background(), strings.Join(run, " "), true)
+		if err != nil || entries == nil {
+			return err
+		}
+
+		run = run[:0]
+		res = append(res, simplestResultSet(entries)...)
+		return nil
+	}
+
+	for _, word := range strings.Split(word, " ") {
+		if !strings.HasPrefix(word, "{") {
+			run = append(run, word)
+			continue
+		}
+
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		if len(run) > 0 {
+			return nil, nil
+		}
+
+		res = append(res, Result{ID: word})
+	}
+	if err := flush(); err != nil || len(run) > 0 {
+		return nil, err
+	}
+
+	for i := range res {
+		res[i].Position = i + 1
+	}
+
+	return res, nil
+}
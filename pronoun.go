@@ -6,6 +6,9 @@ func PronounFromEntry(entry Entry) *Node {
 	return GeneratePronoun(strings.ToLower(entry.Word)).AndThenResult(entry.ID)
 }
 
+// GeneratePronoun generates the tree for a pronoun. The genitives (e.g. oe -> oeyä) and the other changes
+// go by how the pronoun ends, so they are rules for the word class rather than irr: flags, which are for
+// the one-off forms. They also work for the pronouns that are generated without an entry.
 func GeneratePronoun(word string) *Node {
 	switch {
 	case strings.HasSuffix(word, "yä"),
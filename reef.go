@@ -0,0 +1,141 @@
+package lutral
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ForestToReef converts the text from the Forest spelling to the Reef spelling, word by word. It's the
+// same conversion as the Reef spellings in the dictionary: ejectives become voiced stops, tsy and sy become
+// ch and sh, and vowels with a tìftang between them are merged.
+func ForestToReef(text string) string {
+	return convertWords(text, generateReefSpelling)
+}
+
+// ReefToForest converts the text from the Reef spelling to the Forest spelling. A Reef word can come from
+// many Forest words (e.g. g can be kx, and ng can be ng or nkx), so the one that is found in the dictionary
+// with a Forest spelling is picked. Words that are not found that way are left as they are.
+func (dictionary *Dictionary) ReefToForest(text string) string {
+	runner := dictionary.Runner()
+	runner.StrictForest = true
+	runner.PhraseMap = nil
+
+	return convertWords(text, func(word string) string {
+		candidates := forestCandidates(word)
+		for _, candidate := range candidates {
+			if len(runner.Run(candidate)) > 0 {
+				return candidate
+			}
+		}
+
+		return word
+	})
+}
+
+// forestCandidates lists the Forest spellings that the Reef word could be, the most likely first.
+func forestCandidates(word string) []string {
+	res := []string{""}
+	add := func(options ...string) {
+		// Go with the most likely option when there are too many combinations to try.
+		if len(res)*len(options) > maxForestCandidates {
+			options = options[:1]
+		}
+
+		next := make([]string, 0, len(res)*len(options))
+		for _, option := range options {
+			for _, candidate := range res {
+				next = append(next, candidate+option)
+			}
+		}
+		res = next
+	}
+
+	var prev rune
+	for len(word) > 0 {
+		ch, size := utf8.DecodeRuneInString(word)
+
+		switch {
+		case strings.HasPrefix(word, "n-g"):
+			add("nkx")
+			size = len("n-g")
+		case strings.HasPrefix(word, "ng"):
+			add("ng", "nkx")
+			size = len("ng")
+		case strings.HasPrefix(word, "ch"):
+			add("tsy")
+			size = len("ch")
+		case strings.HasPrefix(word, "sh"):
+			add("sy", "sh")
+			size = len("sh")
+		case ch == 'b':
+			add("px")
+		case ch == 'd':
+			add("tx")
+		case ch == 'g':
+			add("kx")
+		case strings.ContainsRune(vowels, ch) && strings.ContainsRune(vowels, prev):
+			add(string(ch), "'"+string(ch))
+		default:
+			add(word[:size])
+		}
+
+		prev, _ = utf8.DecodeLastRuneInString(word[:size])
+		word = word[size:]
+	}
+
+	return res
+}
+
+// maxForestCandidates stops forestCandidates from trying every combination in long words.
+const maxForestCandidates = 256
+
+// convertWords calls convert on each word of the text, keeping the punctuation and the capital letters, see
+// matchCase.
+func convertWords(text string, convert func(word string) string) string {
+	sb := strings.Builder{}
+	sb.Grow(len(text))
+
+	for len(text) > 0 {
+		end := strings.IndexFunc(text, func(ch rune) bool {
+			return !isWordRune(ch)
+		})
+		if end == -1 {
+			end = len(text)
+		}
+
+		if end > 0 {
+			word := text[:end]
+			sb.WriteString(matchCase(word, convert(strings.ToLower(word))))
+			text = text[end:]
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(text)
+		sb.WriteString(text[:size])
+		text = text[size:]
+	}
+
+	return sb.String()
+}
+
+// matchCase gives the converted word the capital letters of the original. The letters do not line up
+// when the conversion changes the length (e.g. tx to d), so it's either all capitals or a capital first
+// letter.
+func matchCase(word, converted string) string {
+	if strings.IndexFunc(word, unicode.IsLower) == -1 && strings.IndexFunc(word, unicode.IsUpper) != -1 {
+		return strings.ToUpper(converted)
+	}
+
+	first, _ := utf8.DecodeRuneInString(word)
+	if unicode.IsUpper(first) {
+		convertedFirst, size := utf8.DecodeRuneInString(converted)
+		return string(unicode.ToUpper(convertedFirst)) + converted[size:]
+	}
+
+	return converted
+}
+
+func isWordRune(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '\'' || ch == '-'
+}
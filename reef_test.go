@@ -0,0 +1,58 @@
+package lutral
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForestToReef(t *testing.T) {
+	table := []struct {
+		Forest string
+		Reef   string
+	}{
+		{"Kaltxì, ma kifkey!", "Kaldì, ma kifkey!"},
+		{"Oel ngati kameie.", "Oel ngati kameie."},
+		{"Tsyal syaw ngä'än.", "Chal shaw ngään."},
+		{"kxanì pxel txon", "ganì bel don"},
+		{"tìsyìmawnun'i", "tìshìmawnun'i"},
+		{"sänkx, skxawng!", "sän-g, skxawng!"},
+		{"KALTXÌ, Ma Kifkey!", "KALDÌ, Ma Kifkey!"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Forest, func(t *testing.T) {
+			assert.Equal(t, row.Reef, ForestToReef(row.Forest))
+		})
+	}
+}
+
+func TestDictionary_ReefToForest(t *testing.T) {
+	dict := miniDict()
+	dict.Insert(*ParseEntry("-1:ngä'än:n."))
+	dict.Insert(*ParseEntry("-2:ninkxan:n."))
+	dict.Insert(*ParseEntry("-3:kxanì:adj."))
+	dict.Insert(*ParseEntry("-4:tsyal:n."))
+
+	table := []struct {
+		Reef   string
+		Forest string
+	}{
+		{"Kaldì, ma kifkey!", "Kaltxì, ma kifkey!"},
+		{"Ngään aganì ngäänit.", "Ngä'än akxanì ngä'änit."},
+		{"Ganì ninganìl", "Kxanì ninkxanìl"},
+		{"nin-gan", "ninkxan"},
+		{"Chal, eltu herahaw", "Tsyal, eltu herahaw"},
+		{"fraeltut", "fraeltut"},
+		{"glurb", "glurb"},
+		{"Glurb gurb", "Glurb gurb"},
+		{"KALDÌ, MA KIFKEY!", "KALTXÌ, MA KIFKEY!"},
+		{"NGÄÄNIT", "NGÄ'ÄNIT"},
+	}
+
+	for _, row := range table {
+		t.Run(row.Reef, func(t *testing.T) {
+			assert.Equal(t, row.Forest, dict.ReefToForest(row.Reef))
+		})
+	}
+}
@@ -16,6 +16,63 @@ type Result struct {
 	Suffixes  []string `json:"suffixes,omitempty"`
 	Lenitions []string `json:"lenitions,omitempty"`
 	Particles []string `json:"particles,omitempty"`
+	// Form is the part of the text the result was found in. It's only set by Extract.
+	Form string `json:"form,omitempty"`
+	// Spelling is the spelling variant of the entry that matched.
+	Spelling Spelling `json:"spelling,omitempty"`
+	// Folded is set if the text only matched by leaving out diacritics, see Runner.FoldDiacritics.
+	Folded bool `json:"folded,omitempty"`
+	// Number is the value of the numeral, if it is one.
+	Number *int `json:"number,omitempty"`
+	// BasePoS is the PoS of the entry the word is derived from. It's only set along with Derivation.
+	BasePoS string `json:"basePos,omitempty"`
+	// Derivation is what kind of derived word it is, see the Derivation constants.
+	Derivation string `json:"derivation,omitempty"`
+	// Slots has the words that filled the slots of a phrase, in order. An unknown word in an {any} slot has
+	// only its Position and Form.
+	Slots []Result `json:"slots,omitempty"`
+	// Score is the cost of the result given by a Scorer, lower being more plausible. It's only set by Rank.
+	Score float64 `json:"score,omitempty"`
+
+	// irregular is set for results from an irregular form until the regular forms they replace are dropped.
+	irregular bool
+}
+
+// The derivations set by the generators.
+const (
+	DerivationGerund            = "gerund"
+	DerivationAgent             = "agent"
+	DerivationAbility           = "ability"
+	DerivationInstrument        = "instrument"
+	DerivationActiveParticiple  = "active-participle"
+	DerivationPassiveParticiple = "passive-participle"
+	DerivationTsukAdjective     = "tsuk-adjective"
+	DerivationNgaAdjective      = "nga-adjective"
+	DerivationLeAdjective       = "le-adjective"
+	DerivationAdverb            = "adverb"
+)
+
+// derivedResultValue makes the value of a result node for a derived word, which is "ID:PoS:BasePoS:Derivation"
+// instead of just "ID[:PoS]".
+func derivedResultValue(id, pos, basePoS, derivation string) string {
+	return id + ":" + pos + ":" + basePoS + ":" + derivation
+}
+
+// parseResultValue reads the value of a result node, "ID[:PoS[:BasePoS:Derivation]][@Spelling]", into a
+// result. The spelling is left empty if there is none.
+func parseResultValue(value string) Result {
+	value, spelling, _ := strings.Cut(value, "@")
+	split := strings.Split(value, ":")
+	res := Result{ID: split[0], Spelling: Spelling(spelling)}
+	if len(split) > 1 {
+		res.PoS = split[1]
+	}
+	if len(split) > 3 {
+		res.BasePoS = split[2]
+		res.Derivation = split[3]
+	}
+
+	return res
 }
 
 func (result *Result) String() string {
@@ -32,6 +89,10 @@ func (result *Result) String() string {
 		sb.WriteString(result.PoS)
 	}
 
+	if result.Number != nil {
+		_, _ = fmt.Fprintf(&sb, " (%d)", *result.Number)
+	}
+
 	if len(result.Prefixes) > 0 {
 		sb.WriteRune(' ')
 		for _, prefix := range result.Prefixes {
@@ -90,12 +151,13 @@ func (result *Result) String() string {
 }
 
 func (result *Result) CoveredBy(template Result) bool {
-	return result.ID == template.ID && result.PoS == template.PoS &&
+	return result.ID == template.ID && result.PoS == template.PoS && result.Derivation == template.Derivation &&
 		sliceCovered(template.Prefixes, result.Prefixes) &&
 		sliceCovered(template.Suffixes, result.Suffixes) &&
 		sliceCovered(template.Infixes, result.Infixes) &&
 		sliceCovered(template.Lenitions, result.Lenitions) &&
-		sliceCovered(template.Particles, result.Particles)
+		sliceCovered(template.Particles, result.Particles) &&
+		(template.Number == nil || (result.Number != nil && *result.Number == *template.Number))
 }
 
 func (result *Result) AddAffixesFrom(other, template Result) {
@@ -145,6 +207,12 @@ func simplestResultSet(results []Result) []Result {
 		}
 	}
 
+	// A phrase matches the words whichever form or spelling they were found in.
+	for i := range res {
+		res[i].Form = ""
+		res[i].Spelling = ""
+	}
+
 	return res
 }
 
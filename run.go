@@ -1,6 +1,8 @@
 package lutral
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"unicode/utf8"
@@ -10,12 +12,49 @@ type Runner struct {
 	Root       *Node
 	SubtreeMap map[string]*Node
 	PhraseMap  map[string][]Result
+	// NumeralRoot is looked up along with Root, so that the numerals are found without being in it. The
+	// dictionary's runners have the NumeralTree here.
+	NumeralRoot *Node
+	// NumeralMap has the result values ("ID[:PoS]") to use for numerals that are listed as entries.
+	NumeralMap map[int]string
+	// IrregularMap has the irregular forms' results for each ID. The regular results like them are dropped.
+	IrregularMap map[string][]Result
+	// LemmaMap has the PoS of the entries, for the phrase slots to check the results without one.
+	LemmaMap map[string]Lemma
+	// Adpositions lists the adpositions as they are written as affixes. A result with one of them as a prefix
+	// is dropped if it has a case ending, and Disambiguate looks for them as suffixes.
+	Adpositions []string
 
 	StepCount    int64
 	SubStepCount int64
 
+	// MaxStepCount limits how many steps a single lookup may take. Zero means no limit.
+	MaxStepCount int64
+
+	// Normalize is applied to the text before looking it up. It's NormalizeText if not set.
+	Normalize func(text string) string
+	// FoldDiacritics lets plain letters in the text match the letters with diacritics (e.g. "a" for
+	// "ä"), for text typed without them. The results that needed it have Folded set.
+	FoldDiacritics bool
+	// Tokenizer decides where the words end. It's DefaultTokenizer if not set.
+	Tokenizer Tokenizer
+	// StrictForest leaves out the results found with the Reef spellings, see Spelling.IsReef.
+	StrictForest bool
+	// Scorer ranks the results of Run, and the results at each position of Extract. They're in the order
+	// they were found in if it's not set.
+	Scorer Scorer
+
 	res      []Result
 	isSorted bool
+	infixMap map[string][]infix
+	// keepUnknown makes extract add a result without an ID for the words it does not know.
+	keepUnknown bool
+	// wordCount is how many words the last extract went through, including the ones in phrases.
+	wordCount int
+
+	ctx       context.Context
+	stepStart int64
+	err       error
 }
 
 const (
@@ -24,55 +63,164 @@ const (
 	mandatoryLenition = 2
 )
 
+// Run looks up the text. It panics if the tree is malformed, use RunContext to get an error instead.
 func (runner *Runner) Run(text string) []Result {
-	if runner.SubtreeMap == nil {
-		runner.SubtreeMap = GenerateInitialSubTreeMap()
-	}
+	res, err := runner.RunContext(context.Background(), text)
+	panicOnTreeError(err)
 
-	runner.res = runner.res[:0]
-	runner.runStep(runner.Root, strings.ToLower(text), allowLenition, "", nil)
+	return res
+}
 
-	return append(runner.res[:0:0], runner.res...)
+// RunContext is like Run, but it stops early if the context is done or if the lookup takes more than
+// MaxStepCount steps. In that case, it returns the results found so far along with a *LimitError.
+// A malformed tree gives a *SubTreeError instead.
+func (runner *Runner) RunContext(ctx context.Context, text string) ([]Result, error) {
+	runner.begin(ctx)
+
+	text = runner.normalize(text)
+	runner.runStep(runner.Root, text, allowLenition, "", nil)
+	if runner.NumeralRoot != nil {
+		runner.runStep(runner.NumeralRoot, text, allowLenition, "", nil)
+	}
+	runner.dropReplacedRegulars(0)
+	runner.dropAdpositionCases(0)
+	if runner.Scorer != nil {
+		Rank(runner.res, runner.Scorer)
+	}
+
+	return append(runner.res[:0:0], runner.res...), runner.end()
 }
 
 // Extract is like run, but it works through the text from left to right, returning all the entries that
 // are the longest at each step (i.e. a si-verb wins over its noun or adjective component).
 func (runner *Runner) Extract(text string) []Result {
-	return runner.extract(text, false)
+	res, err := runner.extract(context.Background(), text, false)
+	panicOnTreeError(err)
+
+	return res
+}
+
+// ExtractContext is like Extract, but with the same limits as RunContext. The partial results only
+// contains the words fully processed before the limit was hit.
+func (runner *Runner) ExtractContext(ctx context.Context, text string) ([]Result, error) {
+	return runner.extract(ctx, text, false)
 }
 
 func (runner *Runner) ExtractWithoutSkipping(text string) []Result {
-	return runner.extract(text, true)
+	res, err := runner.extract(context.Background(), text, true)
+	panicOnTreeError(err)
+
+	return res
+}
+
+// panicOnTreeError keeps the old behavior of the non-context functions, where limits were not a
+// thing but a broken tree would panic.
+func panicOnTreeError(err error) {
+	var limitErr *LimitError
+	if err != nil && !errors.As(err, &limitErr) {
+		panic(err)
+	}
 }
 
-func (runner *Runner) extract(text string, doNotSkip bool) []Result {
+func (runner *Runner) begin(ctx context.Context) {
 	if runner.SubtreeMap == nil {
 		runner.SubtreeMap = GenerateInitialSubTreeMap()
 	}
+	if runner.infixMap == nil {
+		runner.infixMap = infixMap
+	}
+	if runner.Tokenizer == nil {
+		runner.Tokenizer = DefaultTokenizer{}
+	}
 
 	runner.res = runner.res[:0]
+	runner.ctx = ctx
+	runner.stepStart = runner.StepCount
+	runner.err = nil
+}
+
+func (runner *Runner) end() error {
+	err := runner.err
+	runner.ctx = nil
+	runner.err = nil
+
+	return err
+}
+
+// checkLimits is called for every step, and it returns false once the lookup should stop.
+func (runner *Runner) checkLimits() bool {
+	if runner.err != nil {
+		return false
+	}
+
+	steps := runner.StepCount - runner.stepStart
+	if runner.MaxStepCount > 0 && steps > runner.MaxStepCount {
+		runner.err = &LimitError{StepCount: steps, Err: ErrStepLimitExceeded}
+		return false
+	}
+
+	// Asking the context every step would be wasteful, but it should be checked on the first one.
+	if runner.ctx != nil && steps%contextCheckInterval == 1 {
+		if err := runner.ctx.Err(); err != nil {
+			runner.err = &LimitError{StepCount: steps, Err: err}
+			return false
+		}
+	}
+
+	return true
+}
+
+func (runner *Runner) extract(ctx context.Context, text string, doNotSkip bool) ([]Result, error) {
+	runner.begin(ctx)
 	position := 0
 
-	text = strings.ToLower(text)
-	for ; len(text) > 0; text = strings.Trim(text, punctuation) {
+	// The forms are taken from the original text, unless normalizing changed the length of it.
+	original := text
+	text = runner.normalize(text)
+	if len(original) != len(text) {
+		original = text
+	}
+	lowered := text
+	spans := make([][2]int, 0, 8)
+
+	for text = runner.skipSeparators(text); len(text) > 0; text = runner.skipSeparators(text) {
 		// Record where we are and run.
 		resOffset := len(runner.res)
+		start := len(lowered) - len(text)
 		runner.runStep(runner.Root, text, allowLenition, "", nil)
+		if runner.NumeralRoot != nil {
+			runner.runStep(runner.NumeralRoot, text, allowLenition, "", nil)
+		}
 		position += 1
 
+		// Drop the incomplete word and leave it to the caller.
+		if runner.err != nil {
+			runner.res = runner.res[:resOffset]
+			break
+		}
+
+		runner.dropReplacedRegulars(resOffset)
+		runner.dropAdpositionCases(resOffset)
+
 		// Skip word if no results
 		if len(runner.res) == resOffset {
 			if doNotSkip {
-				return nil
+				return nil, runner.end()
 			}
 
-			next := strings.IndexAny(text, punctuation)
+			next := runner.nextSeparator(text)
 			if next == -1 {
 				text = text[len(text):]
 			} else {
 				text = text[next:]
 			}
 
+			end := len(lowered) - len(text)
+			spans = append(spans, [2]int{start, end})
+			if runner.keepUnknown {
+				runner.res = append(runner.res, Result{Position: position, Form: original[start:end]})
+			}
+
 			continue
 		}
 
@@ -97,8 +245,11 @@ func (runner *Runner) extract(text string, doNotSkip bool) []Result {
 			text = runner.res[resOffset].Remainder
 		}
 
+		end := len(lowered) - len(text)
+		spans = append(spans, [2]int{start, end})
 		for i := range runner.res[resOffset:] {
 			runner.res[resOffset+i].Position = position
+			runner.res[resOffset+i].Form = original[start:end]
 		}
 	}
 
@@ -106,59 +257,16 @@ func (runner *Runner) extract(text string, doNotSkip bool) []Result {
 		runner.res[i].Remainder = ""
 	}
 
-	if position > 1 {
-		for phraseID, phrase := range runner.PhraseMap {
-			if len(runner.res) < len(phrase) {
-				continue
-			}
-			positionFoundMap := uint32(0)
-			phraseResult := Result{ID: phraseID}
-
-			basePosition := len(runner.res)
-			for i := range runner.res {
-				relativePosition := runner.res[i].Position - basePosition
-				if relativePosition < 0 {
-					relativePosition = 0
-				}
-				if relativePosition >= len(phrase) {
-					break
-				}
-
-				if runner.res[i].CoveredBy(phrase[relativePosition]) {
-					if basePosition > runner.res[i].Position {
-						basePosition = runner.res[i].Position
-						phraseResult.Position = basePosition
-					}
-
-					phraseResult.AddAffixesFrom(runner.res[i], phrase[relativePosition])
-					positionFoundMap |= 1 << relativePosition
-				}
-			}
+	runner.wordCount = position
+	if position > 1 && runner.err == nil {
+		runner.matchPhrases(original, spans)
+	}
 
-			if positionFoundMap == (1<<len(phrase))-1 {
-				l := 0
-				added := false
-				redIndex := -1
-
-				for _, res := range runner.res {
-					if res.Position < basePosition || res.Position >= basePosition+len(phrase) {
-						runner.res[l] = res
-						l += 1
-					} else if res.Position == basePosition && !added {
-						runner.res[l] = res
-						redIndex = l
-						l += 1
-						added = true
-					}
-				}
-				runner.res[redIndex] = phraseResult
-				runner.res = runner.res[:l]
-				break
-			}
-		}
+	if runner.Scorer != nil {
+		runner.rankPositions()
 	}
 
-	return runner.res
+	return runner.res, runner.end()
 }
 
 func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, skippableLetter string, returnTo *Node) bool {
@@ -167,6 +275,9 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 	var didProceed bool
 
 	runner.StepCount += 1
+	if !runner.checkLimits() {
+		return false
+	}
 
 	switch node.Kind {
 	case NKRoot:
@@ -176,16 +287,16 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 		didProceed = true
 
 	case NKResult:
-		if remainder == "" || strings.IndexAny(remainder, punctuation) == 0 {
+		if remainder == "" || runner.Tokenizer.SeparatorLength(remainder) > 0 {
 			runner.SubStepCount += 1
-			split := strings.Split(node.Value, ":")
-			res := Result{
-				ID:        split[0],
-				Remainder: remainder,
+			res := parseResultValue(node.Value)
+			if res.Spelling == "" {
+				res.Spelling = SpellingStandard
 			}
-			if len(split) > 1 {
-				res.PoS = split[1]
+			if runner.StrictForest && res.Spelling.IsReef() {
+				break
 			}
+			res.Remainder = remainder
 
 			runner.res = append(runner.res, res)
 			didProceed = true
@@ -236,7 +347,7 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 					for _, matchText := range matchTexts {
 						runner.SubStepCount += 1
 
-						if trimmedRemainder := strings.TrimPrefix(remainder, matchText); trimmedRemainder != remainder || matchText == "" {
+						if trimmedRemainder, ok, folded := runner.cutPrefix(remainder, matchText); ok {
 							resOffset := len(runner.res)
 							for i, child := range node.Children {
 								nextSkippable := nextSkippable
@@ -250,6 +361,9 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 								// This is always the last lenition.
 								runner.res[i+resOffset].Lenitions = []string{lenition}
 							}
+							if folded {
+								runner.markFolded(resOffset)
+							}
 
 							didProceed = true
 						}
@@ -267,7 +381,8 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 			for _, matchText := range matchTexts {
 				runner.SubStepCount += 1
 
-				if trimmedRemainder := strings.TrimPrefix(remainder, matchText); trimmedRemainder != remainder || matchText == "" {
+				if trimmedRemainder, ok, folded := runner.cutPrefix(remainder, matchText); ok {
+					resOffset := len(runner.res)
 					for i, child := range node.Children {
 						nextSkippable := nextSkippable
 						if child.Kind == NKRaw {
@@ -276,6 +391,9 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 
 						runner.runStep(&node.Children[i], trimmedRemainder, noLenition, nextSkippable, returnTo)
 					}
+					if folded {
+						runner.markFolded(resOffset)
+					}
 
 					didProceed = true
 				}
@@ -305,13 +423,17 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 			for _, matchText := range matchTexts {
 				runner.SubStepCount += 1
 
-				if trimmedRemainder := strings.TrimPrefix(remainder, matchText); trimmedRemainder != remainder || matchText == "" {
+				if trimmedRemainder, ok, folded := runner.cutPrefix(remainder, matchText); ok {
+					matchOffset := len(runner.res)
 					for i := range node.Children {
 						runner.runStep(&node.Children[i], trimmedRemainder, nextLenition, nextSkippable, returnTo)
 					}
 					for i, res := range runner.res[resOffset:] {
 						runner.res[i+resOffset].Lenitions = prependToSlice(res.Lenitions, lenition)
 					}
+					if folded {
+						runner.markFolded(matchOffset)
+					}
 
 					didProceed = true
 				}
@@ -327,10 +449,14 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 			for _, matchText := range matchTexts {
 				runner.SubStepCount += 1
 
-				if trimmedRemainder := strings.TrimPrefix(remainder, matchText); trimmedRemainder != remainder || matchText == "" {
+				if trimmedRemainder, ok, folded := runner.cutPrefix(remainder, matchText); ok {
+					matchOffset := len(runner.res)
 					for i := range node.Children {
 						runner.runStep(&node.Children[i], trimmedRemainder, nextLenition, nextSkippable, returnTo)
 					}
+					if folded {
+						runner.markFolded(matchOffset)
+					}
 				}
 
 				didProceed = true
@@ -346,14 +472,14 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 		sorted := node.Value == "0" || node.Value == "1" || node.Value == "2"
 
 	infixLoop:
-		for _, infix := range infixes(infixMap, strings.Split(node.Value, ",")...) {
+		for _, infix := range infixes(runner.infixMap, strings.Split(node.Value, ",")...) {
 			runner.SubStepCount += 1
 
-			if afterInfix := strings.TrimPrefix(remainder, infix.Match); afterInfix != remainder || infix.Match == "" {
+			if afterInfix, ok, folded := runner.cutPrefix(remainder, infix.Match); ok {
 				prevFit = infix.Match != ""
 
 				for _, notBefore := range infix.NotBefore {
-					if strings.HasPrefix(afterInfix, notBefore) {
+					if _, found, _ := runner.cutPrefix(afterInfix, notBefore); found {
 						continue infixLoop
 					}
 				}
@@ -362,8 +488,9 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 					found := false
 					for _, onlyBefore := range infix.OnlyBefore {
 						runner.SubStepCount += 1
-						if strings.HasPrefix(afterInfix, onlyBefore) {
+						if _, foundBefore, foldedBefore := runner.cutPrefix(afterInfix, onlyBefore); foundBefore {
 							found = true
+							folded = folded || foldedBefore
 							break
 						}
 					}
@@ -384,6 +511,9 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 						runner.res[i+resOffset].Infixes = prependToSlice(res.Infixes, infix.Name)
 					}
 				}
+				if folded {
+					runner.markFolded(resOffset)
+				}
 
 				didProceed = true
 			} else if sorted && prevFit {
@@ -412,7 +542,7 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 		}
 
 		for _, matchText := range matchTexts {
-			if afterSuffix := strings.TrimPrefix(remainder, matchText); afterSuffix != remainder {
+			if afterSuffix, ok, folded := runner.cutPrefix(remainder, matchText); ok && matchText != "" {
 				resOffset := len(runner.res)
 				for i := range node.Children {
 					runner.runStep(&node.Children[i], afterSuffix, noLenition, nextSkippable, returnTo)
@@ -420,6 +550,9 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 				for i, res := range runner.res[resOffset:] {
 					runner.res[i+resOffset].Suffixes = prependToSlice(res.Suffixes, suffixName)
 				}
+				if folded {
+					runner.markFolded(resOffset)
+				}
 
 				didProceed = true
 				break
@@ -429,7 +562,8 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 	case NKSubTree:
 		subTree := runner.SubtreeMap[node.Value]
 		if subTree == nil {
-			panic("unknown subtree " + node.Value)
+			runner.err = &SubTreeError{Name: node.Value, Err: ErrUnknownSubTree}
+			return false
 		}
 
 		nextReturnTo := returnTo
@@ -441,7 +575,8 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 
 	case NKReturn:
 		if returnTo == nil {
-			panic("nowhere to /return to")
+			runner.err = &SubTreeError{Err: ErrNowhereToReturn}
+			return false
 		}
 
 		for i := range returnTo.Children {
@@ -457,7 +592,7 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 			particleName = particleMatch
 		}
 
-		if afterParticle := strings.TrimPrefix(remainder, particleMatch); afterParticle != remainder {
+		if afterParticle, ok, folded := runner.cutPrefix(remainder, particleMatch); ok && particleMatch != "" {
 			resOffset := len(runner.res)
 			for i := range node.Children {
 				runner.runStep(&node.Children[i], afterParticle, noLenition, "", returnTo)
@@ -465,10 +600,56 @@ func (runner *Runner) runStep(node *Node, remainder string, lenitionState int, s
 			for i, res := range runner.res[resOffset:] {
 				runner.res[i+resOffset].Particles = prependToSlice(res.Particles, particleName)
 			}
+			if folded {
+				runner.markFolded(resOffset)
+			}
+
+			didProceed = true
+		}
+
+	case NKNumeral:
+		ordinal := node.Value == "ve"
+		for _, match := range numeralPrefixes(remainder, ordinal, runner.FoldDiacritics, nil) {
+			runner.SubStepCount += 1
+
+			resOffset := len(runner.res)
+			for i := range node.Children {
+				runner.runStep(&node.Children[i], remainder[match.Length:], noLenition, "", returnTo)
+			}
+			if match.Folded {
+				runner.markFolded(resOffset)
+			}
+			for i := range runner.res[resOffset:] {
+				res := &runner.res[i+resOffset]
+				if res.ID == NumeralID {
+					if resultValue, ok := runner.NumeralMap[match.Value]; ok {
+						res.ID, res.PoS, _ = strings.Cut(resultValue, ":")
+					}
+				}
+				if ordinal {
+					res.Suffixes = prependToSlice(res.Suffixes, node.Value)
+				}
+
+				value := match.Value
+				res.Number = &value
+			}
 
 			didProceed = true
 		}
 
+	case NKIrregular:
+		resOffset := len(runner.res)
+		for i := range node.Children {
+			if runner.runStep(&node.Children[i], remainder, noLenition, "", returnTo) {
+				didProceed = true
+			}
+		}
+		for i := range runner.res[resOffset:] {
+			res := &runner.res[i+resOffset]
+			addIrregularAffixes(res, strings.Fields(node.Value))
+			res.irregular = true
+		}
+
 	case NKLeafHook:
 		// Do nothing, this one is just for helping tree generation.
 	}
@@ -486,110 +667,15 @@ func prependToSlice[T any, S ~[]T](slice S, value T) S {
 	return slice
 }
 
+// GenerateInitialSubTreeMap returns a fresh copy of the subtrees in the default grammar.
 func GenerateInitialSubTreeMap() map[string]*Node {
-	return map[string]*Node{
-		// Noun prefixes
-		"np": CombineTrees(
-			BuildTree("$np2"),
-			BuildTree("me+|pxe+|ay+", "$np2"),
-			BuildTree("fì-|tsa-|pe+", "$np2"),
-			BuildTree("fì-|tsa-|pe+", "me+|pxe+|ay+", "$np2"),
-			BuildTree("fra-", "$np2"),
-			BuildTree("fra-", "ay+", "$np2"),
-			BuildTree("fay+|pay+", "$np2"),
-		),
-		// Noun prefixes for modifying the noun (called by np or np_numbers only)
-		"np2": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("fne-|sna-|munsna-", "/return"),
-		),
-		// Noun suffixes that modify
-		"nsmod": CombineTrees(
-			BuildTree("$nsadp|$nsto"),
-			BuildTree("-fkeyk", "$nsmod_fkeyk"),
-			BuildTree("-tsyìp", "$nsmod_tsyìp"),
-			BuildTree("-o", "$nsto|$ncevou|$nsadp"),
-			BuildTree("-pe", "$nsto|$ncev|$nsadp"),
-		),
-		// Noun suffixed that modify and can follow -fkeyk
-		"nsmod_fkeyk": CombineTrees(
-			BuildTree("$ncec|$nsto|$nsadp"),
-			BuildTree("-tsyìp", "$ncec|$nsmod_tsyìp|$nsadp"),
-			BuildTree("-o", "$ncevou|$nsadp"),
-			BuildTree("-pe", "$nsto|$ncev|$nsadp"),
-		),
-		// Noun suffixes that modify and can follow -tsyìp
-		"nsmod_tsyìp": CombineTrees(
-			BuildTree("$ncec|$nsto|$nsadp"),
-			BuildTree("-o", "$ncevou|$nsadp"),
-			BuildTree("-pe", "$nsto|$ncev|$nsadp"),
-		),
-		// Modify noun-part of si-verbs
-		"nsmod_si": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("-o|-pe", "$nsto|/return"),
-			BuildTree("-tsyìp", "$nsto|/return"),
-			BuildTree("-tsyìp", "-o|-pe", "$nsto|/return"),
-		),
-		// Allow -to or return
-		"nsto": BuildTree("-to", "/return"),
-		// Noun suffixes from adpositions. To be filled by Dictionary
-		"nsadp": EmptyTree(),
-		// Noun case endings: vowels
-		"ncev": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("-l|-t|-ti|-r|-ru|-ri|-yä|-ye=yä", "/return"),
-		),
-		// Noun case endings: after "ia"
-		"nceia": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("-l|-t|-ti|-r|-ru|-ri", "/return"),
-			BuildTree("-yä", "/return"), // todo: error node
-		),
-		// Noun case endings: after "o"/"u"
-		"ncevou": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("-l|-t|-ti|-r|-ru|-ri|-ä|-e=ä", "/return"),
-		),
-		// Noun case endings: consonants
-		"ncec": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("-ìl|-ti|-it|-ur|-ìri|-ä|-e=ä", "/return"),
-		),
-		// Noun case endings: loan words (replacing ì)
-		"ncevìlw": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("-ìl|-it|-ur|-ìri|-ä|-e=ä", "/return"),
-		),
-		// Noun case endings: consonant "t"
-		"ncect": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("-ìl|-it|-ur|-ìri|-ä|-e=ä", "/return"),
-		),
-		// Noun case endings: consonant "'" (tìftang)
-		"ncec'": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("-ìl|-ti|-it|-ur|-ìri|-ä|-e=ä", "/return"),
-		),
-		// Noun case endings: diphthongs "ay"/"ey"
-		"ncedy": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("-l|-ìl|-t|-ti|-ur|-ru|-ri|-ä|-e=ä", "/return"),
-			BuildTree("-it|-ìri", "/return"), // todo: error node
-		),
-		// Noun case endings: diphthongs "aw"/"ew"
-		"ncedw": CombineTrees(
-			BuildTree("/return"),
-			BuildTree("-l|-ìl|-ti|-it|-r|-ur|-ri|-ä|-e=ä", "/return"),
-		),
-		// Pronoun-specific case endings
-		"pce_o": CombineTrees(
-			BuildTree("-l|-t|-ti|-r|-ru|-ri", "/return"),
-		),
-		"pce_ng_a": CombineTrees(
-			BuildTree("-l|-t|-ti|-r|-ru|-ri", "/return"),
-		),
+	res := make(map[string]*Node, len(defaultGrammar.SubTreeMap))
+	for name, tree := range defaultGrammar.SubTreeMap {
+		res[name] = CopyTree(*tree)
 	}
+
+	return res
 }
 
-const punctuation = " ,;.…—–-?!"
+// contextCheckInterval is how many steps there are between checking if the context is done.
+const contextCheckInterval = 256
@@ -1,6 +1,7 @@
 package lutral
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -142,18 +143,32 @@ func TestRunner_Extract(t *testing.T) {
 	}
 }
 
-func TestRunner_runStep_Panic(t *testing.T) {
+func TestRunner_Run_Panic(t *testing.T) {
 	assert.Panics(t, func() {
-		runner := &Runner{}
-		runner.runStep(&Node{Kind: NKReturn}, "glurb", 0, "", nil)
+		runner := &Runner{Root: &Node{Kind: NKReturn}}
+		runner.Run("glurb")
 	})
 
 	assert.Panics(t, func() {
-		runner := &Runner{SubtreeMap: map[string]*Node{}}
-		runner.runStep(&Node{Kind: NKSubTree}, "non_existent", 0, "", nil)
+		runner := &Runner{Root: &Node{Kind: NKSubTree, Value: "non_existent"}, SubtreeMap: map[string]*Node{}}
+		runner.Extract("glurb")
 	})
 }
 
+func TestRunner_RunContext_TreeErrors(t *testing.T) {
+	runner := &Runner{Root: BuildTree("glurb", "/return")}
+	_, err := runner.RunContext(context.Background(), "glurb")
+	assert.ErrorIs(t, err, ErrNowhereToReturn)
+	assert.EqualError(t, err, "root tree: nowhere to /return to")
+
+	runner = &Runner{Root: BuildTree("$np", "glurb", "=1"), SubtreeMap: map[string]*Node{
+		"np": BuildTree("$np2"),
+	}}
+	_, err = runner.RunContext(context.Background(), "glurb")
+	assert.ErrorIs(t, err, ErrUnknownSubTree)
+	assert.EqualError(t, err, "subtree $np2: unknown subtree")
+}
+
 func BenchmarkGenerateInitialSubTreeMap(b *testing.B) {
 	for n := 0; n < b.N; n++ {
 		gen := GenerateInitialSubTreeMap()
@@ -162,3 +177,60 @@ func BenchmarkGenerateInitialSubTreeMap(b *testing.B) {
 		}
 	}
 }
+
+func TestRunner_RunContext(t *testing.T) {
+	dict := buildTestTree()
+
+	t.Run("NoLimit", func(t *testing.T) {
+		runner := &Runner{Root: dict}
+		res, err := runner.RunContext(context.Background(), "fmäpeykìlmetängok")
+		assert.NoError(t, err)
+		assert.Equal(t, runner.Run("fmäpeykìlmetängok"), res)
+	})
+
+	t.Run("StepLimit", func(t *testing.T) {
+		runner := &Runner{Root: dict, MaxStepCount: 10}
+		res, err := runner.RunContext(context.Background(), "fmäpeykìlmetängok")
+		assert.ErrorIs(t, err, ErrStepLimitExceeded)
+		assert.Empty(t, res)
+
+		var limitErr *LimitError
+		if assert.ErrorAs(t, err, &limitErr) {
+			assert.Equal(t, int64(11), limitErr.StepCount)
+		}
+
+		// The limit is per lookup, not for the runner's lifetime.
+		runner.MaxStepCount = 100000
+		res, err = runner.RunContext(context.Background(), "fmetok")
+		assert.NoError(t, err)
+		assert.Len(t, res, 1)
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		runner := &Runner{Root: dict}
+		res, err := runner.RunContext(ctx, "fmetok")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, res)
+	})
+}
+
+func TestRunner_ExtractContext(t *testing.T) {
+	dict := buildTestTree()
+
+	runner := &Runner{Root: dict}
+	full, err := runner.ExtractContext(context.Background(), "fmetok fìuvanti, ma eylan")
+	assert.NoError(t, err)
+	assert.Len(t, full, 4)
+
+	stepsPerWord := runner.StepCount / 4
+
+	runner = &Runner{Root: dict, MaxStepCount: stepsPerWord * 2}
+	res, err := runner.ExtractContext(context.Background(), "fmetok fìuvanti, ma eylan")
+	assert.ErrorIs(t, err, ErrStepLimitExceeded)
+	if assert.NotEmpty(t, res) && assert.Less(t, len(res), len(full)) {
+		assert.Equal(t, full[:len(res)], res)
+	}
+}
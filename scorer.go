@@ -0,0 +1,100 @@
+package lutral
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// Scorer gives a result a cost, where the lowest cost is the most plausible reading.
+type Scorer interface {
+	Score(result Result) float64
+}
+
+// DefaultScorer adds up the costs of what had to be done to the word to find the result. Each affix and
+// lenition has a cost, and so does using an alternative spelling or folding diacritics. Setting Frequencies
+// lowers the cost of the common words, so a common word with an affix can beat a rare one without.
+type DefaultScorer struct {
+	// AffixCost is added for each prefix, infix, suffix and particle.
+	AffixCost float64
+	// LenitionCost is added for each lenition.
+	LenitionCost float64
+	// RareInfixCost is added on top of AffixCost for each infix in RareInfixes.
+	RareInfixCost float64
+	RareInfixes   []string
+	// SpellingCost is added if the result was found with a spelling other than SpellingStandard.
+	SpellingCost float64
+	// FoldedCost is added if the result needed Runner.FoldDiacritics.
+	FoldedCost float64
+
+	// Frequencies has the number of times each ID is seen in a corpus. The cost is lowered by
+	// FrequencyWeight times the logarithm of it.
+	Frequencies     map[string]int
+	FrequencyWeight float64
+}
+
+// NewDefaultScorer creates a DefaultScorer where every affix and lenition costs the same, and the reflexive
+// causative, the honorific and the combined tense and aspect infixes are rare.
+func NewDefaultScorer() *DefaultScorer {
+	return &DefaultScorer{
+		AffixCost:       1,
+		LenitionCost:    1,
+		RareInfixCost:   1,
+		RareInfixes:     []string{"äpeyk", "ìsy", "asy", "ìrm", "ìry", "ary", "ìlm", "ìly", "aly", "uy"},
+		SpellingCost:    0.5,
+		FoldedCost:      0.5,
+		FrequencyWeight: 0.25,
+	}
+}
+
+func (scorer *DefaultScorer) Score(result Result) float64 {
+	score := 0.0
+
+	affixCount := len(result.Prefixes) + len(result.Infixes) + len(result.Suffixes) + len(result.Particles)
+	score += float64(affixCount) * scorer.AffixCost
+	score += float64(len(result.Lenitions)) * scorer.LenitionCost
+	for _, infix := range result.Infixes {
+		if slices.Contains(scorer.RareInfixes, infix) {
+			score += scorer.RareInfixCost
+		}
+	}
+
+	if result.Spelling != "" && result.Spelling != SpellingStandard {
+		score += scorer.SpellingCost
+	}
+	if result.Folded {
+		score += scorer.FoldedCost
+	}
+
+	if frequency := scorer.Frequencies[result.ID]; frequency > 0 {
+		score -= scorer.FrequencyWeight * math.Log(float64(frequency))
+	}
+
+	return score
+}
+
+// Rank sets the Score of the results and sorts them by it, lowest first. The results with the same score
+// keep their order.
+func Rank(results []Result, scorer Scorer) []Result {
+	for i := range results {
+		results[i].Score = scorer.Score(results[i])
+	}
+
+	slices.SortStableFunc(results, func(a, b Result) int {
+		return cmp.Compare(a.Score, b.Score)
+	})
+
+	return results
+}
+
+// rankPositions ranks the results of extract at each position separately, so they stay in the order of
+// the text.
+func (runner *Runner) rankPositions() {
+	start := 0
+	for i := 1; i <= len(runner.res); i++ {
+		if i == len(runner.res) || runner.res[i].Position != runner.res[start].Position {
+			Rank(runner.res[start:i], runner.Scorer)
+			start = i
+		}
+	}
+}
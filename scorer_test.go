@@ -0,0 +1,90 @@
+package lutral
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultScorer_Score(t *testing.T) {
+	table := []struct {
+		Result   Result
+		Expected float64
+	}{
+		{Result{ID: "800"}, 0},
+		{Result{ID: "800", Spelling: SpellingStandard}, 0},
+		{Result{ID: "800", Prefixes: []string{"ay"}, Suffixes: []string{"ri"}}, 2},
+		{Result{ID: "2644", Infixes: []string{"ol"}}, 1},
+		{Result{ID: "2644", Infixes: []string{"ìsy", "ei"}}, 3},
+		{Result{ID: "800", Lenitions: []string{"k→h"}, Spelling: SpellingReef}, 1.5},
+		{Result{ID: "692", Particles: []string{"sì"}, Folded: true}, 1.5},
+		{Result{ID: "1056", Suffixes: []string{"ru"}}, 1 - 0.25*math.Log(100)},
+		{Result{ID: "1380", Suffixes: []string{"ru"}}, 1},
+	}
+
+	scorer := NewDefaultScorer()
+	scorer.Frequencies = map[string]int{"1056": 100, "1380": 1}
+
+	for _, row := range table {
+		t.Run(row.Result.String(), func(t *testing.T) {
+			assert.InDelta(t, row.Expected, scorer.Score(row.Result), 0.001)
+		})
+	}
+}
+
+func TestDictionary_Lookup_Ranked(t *testing.T) {
+	dict := miniDict()
+	dict.Insert(*ParseEntry("-1:fkxarat:n."))
+	dict.Insert(*ParseEntry("-2:hu:n."))
+	dict.Insert(*ParseEntry("-3:ku:n."))
+
+	scorer := NewDefaultScorer()
+	dict.Scorer = scorer
+
+	table := []struct {
+		Lookup      string
+		Frequencies map[string]int
+		Expected    string
+	}{
+		{"fkxarat", nil, "-1;11440 -t"},
+		{"fkxarat", map[string]int{"11440": 1000}, "11440 -t;-1"},
+		{"hu", nil, "-2;-3 k→h"},
+		{"tsayuvane", nil, "2644 tsa-ay- -ä;2644 tsa-ay- -ne"},
+		{"uvanterisì letokx", nil, "2644 -teri-sì + letokx;9480 -teri-sì"},
+		{"uvanterisì letokx", map[string]int{"9480": 20}, "9480 -teri-sì;2644 -teri-sì + letokx"},
+	}
+
+	for _, row := range table {
+		t.Run(fmt.Sprintf("%s %v", row.Lookup, row.Frequencies), func(t *testing.T) {
+			scorer.Frequencies = row.Frequencies
+
+			resStr := ""
+			for _, res := range dict.Lookup(row.Lookup) {
+				if len(resStr) != 0 {
+					resStr += ";"
+				}
+				resStr += res.String()
+			}
+			assert.Equal(t, row.Expected, resStr)
+		})
+	}
+}
+
+func TestDictionary_Extract_Ranked(t *testing.T) {
+	dict := miniDict()
+	dict.Insert(*ParseEntry("-2:hu:n."))
+	dict.Insert(*ParseEntry("-3:ku:n."))
+	dict.Scorer = NewDefaultScorer()
+
+	res := dict.Extract("hu tsayuvane")
+	if assert.Len(t, res, 4) {
+		assert.Equal(t, []int{1, 1, 2, 2}, []int{res[0].Position, res[1].Position, res[2].Position, res[3].Position})
+		assert.Equal(t, "-2", res[0].ID)
+		assert.Equal(t, 0.0, res[0].Score)
+		assert.Equal(t, "-3", res[1].ID)
+		assert.Equal(t, 1.0, res[1].Score)
+		assert.Equal(t, 3.0, res[2].Score)
+	}
+}
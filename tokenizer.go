@@ -0,0 +1,85 @@
+package lutral
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokenizer decides where the words in a text are separated. The runner uses it both to find the words
+// in Extract and to check that a result ends at the end of a word.
+type Tokenizer interface {
+	// SeparatorLength returns the length in bytes of the separators at the start of the text, or 0 if
+	// the text starts with a word.
+	SeparatorLength(text string) int
+}
+
+// DefaultTokenizer separates words by whitespace, punctuation, symbols and digits. An apostrophe is a
+// tìftang if a letter follows it, otherwise it can be a closing quote. A word that ends with a tìftang
+// is still found, since the tìftang is matched as part of the word before the separators are checked. An
+// apostrophe before a letter is an opening quote instead if the word ends with another one (e.g. 'kaltxì'),
+// so a word that both starts and ends with a tìftang can't be found when it's written alone. Only the
+// letters up to the next apostrophe are checked for that, so that a long word isn't gone through again at
+// every tìftang. A quoted word with a tìftang in it (e.g. 'ta'leng') is therefore not taken as quoted.
+type DefaultTokenizer struct{}
+
+func (DefaultTokenizer) SeparatorLength(text string) int {
+	length := 0
+	for length < len(text) {
+		ch, size := utf8.DecodeRuneInString(text[length:])
+		if ch == '\'' {
+			next, _ := utf8.DecodeRuneInString(text[length+size:])
+			if unicode.IsLetter(next) && !endsWithQuote(text[length+size:]) {
+				break
+			}
+		} else if !isSeparatorRune(ch) {
+			break
+		}
+
+		length += size
+	}
+
+	return length
+}
+
+// endsWithQuote checks if the word at the start of the text ends with an apostrophe that is not a tìftang,
+// before any other apostrophe. It stops at the first one, see DefaultTokenizer.
+func endsWithQuote(text string) bool {
+	for i, ch := range text {
+		if ch == '\'' {
+			next, _ := utf8.DecodeRuneInString(text[i+1:])
+			return !unicode.IsLetter(next)
+		} else if isSeparatorRune(ch) {
+			return false
+		}
+	}
+
+	return false
+}
+
+func isSeparatorRune(ch rune) bool {
+	return unicode.IsSpace(ch) || unicode.IsPunct(ch) || unicode.IsSymbol(ch) || unicode.IsDigit(ch)
+}
+
+// SeparatorTokenizer separates words by the characters in it only, e.g. SeparatorTokenizer(" ,.").
+type SeparatorTokenizer string
+
+func (separators SeparatorTokenizer) SeparatorLength(text string) int {
+	return len(text) - len(strings.TrimLeft(text, string(separators)))
+}
+
+// skipSeparators removes the separators at the start of the text.
+func (runner *Runner) skipSeparators(text string) string {
+	return text[runner.Tokenizer.SeparatorLength(text):]
+}
+
+// nextSeparator finds the byte offset of the first separator in the text, or -1 if there is none.
+func (runner *Runner) nextSeparator(text string) int {
+	for i := range text {
+		if runner.Tokenizer.SeparatorLength(text[i:]) > 0 {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,96 @@
+package lutral
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultTokenizer_SeparatorLength(t *testing.T) {
+	table := []struct {
+		Text     string
+		Expected int
+	}{
+		{"kaltxì", 0},
+		{"", 0},
+		{" kaltxì", 1},
+		{"\n\tkaltxì", 2},
+		{"(kaltxì)", 1},
+		{"«kaltxì»", 2},
+		{"“kaltxì”", 3},
+		{": 3 ikran", 4},
+		{"'awkx", 0},
+		{"'awkx ma'", 0},
+		{"'kaltxì'", 1},
+		{"'kaltxì', ma", 1},
+		{"'ta'leng'", 0},
+		{"' ma", 2},
+		{"'", 1},
+		{"'.", 2},
+		{"-ìl", 1},
+	}
+
+	for _, row := range table {
+		t.Run(fmt.Sprintf("%q", row.Text), func(t *testing.T) {
+			assert.Equal(t, row.Expected, DefaultTokenizer{}.SeparatorLength(row.Text))
+		})
+	}
+}
+
+func TestDictionary_Extract_Tokenizer(t *testing.T) {
+	dict := miniDict()
+
+	table := []struct {
+		Lookup    string
+		Tokenizer Tokenizer
+		Expected  string
+	}{
+		{"eltu\nherahaw", nil, "[1] 264;[2] 544 <er>"},
+		{"eltu\therahaw", nil, "[1] 264;[2] 544 <er>"},
+		{"(kaltxì)", nil, "[1] 692"},
+		{"«Kaltxì», ma kifkey: 3 eltu", nil, "[1] 692;[2] 1056;[3] 800;[4] 264"},
+		{"“Kaltxì” ma kifkey", nil, "[1] 692;[2] 1056;[3] 800"},
+		{"kifkey' ma", nil, "[1] 800;[2] 1056"},
+		{"'kaltxì' ma kifkey", nil, "[1] 692;[2] 1056;[3] 800"},
+		{"ma 'kifkey'.", nil, "[1] 1056;[2] 800"},
+		{"(kaltxì)", SeparatorTokenizer(" ,;.…—–-?!"), ""},
+		{"eltu\nherahaw", SeparatorTokenizer(" \n"), "[1] 264;[2] 544 <er>"},
+	}
+
+	for _, row := range table {
+		t.Run(fmt.Sprintf("%q", row.Lookup), func(t *testing.T) {
+			runner := dict.Runner()
+			runner.Tokenizer = row.Tokenizer
+
+			resStr := ""
+			for _, res := range runner.Extract(row.Lookup) {
+				if len(resStr) != 0 {
+					resStr += ";"
+				}
+				resStr += res.String()
+			}
+
+			assert.Equal(t, row.Expected, resStr)
+		})
+	}
+}
+
+func TestRunner_nextSeparator(t *testing.T) {
+	runner := Runner{Tokenizer: DefaultTokenizer{}}
+	word := strings.Repeat("a'", 100000) + "a"
+
+	assert.Equal(t, -1, runner.nextSeparator(word))
+	assert.Equal(t, len(word), runner.nextSeparator(word+", ma"))
+	assert.Equal(t, 2, runner.nextSeparator("ma 'kaltxì'"))
+}
+
+func BenchmarkRunner_nextSeparator(b *testing.B) {
+	runner := Runner{Tokenizer: DefaultTokenizer{}}
+	word := strings.Repeat("a'", 10000) + "a"
+
+	for i := 0; i < b.N; i++ {
+		runner.nextSeparator(word)
+	}
+}
@@ -0,0 +1,165 @@
+package lutral
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrMisplacedRoot is found by ValidateTree when a NKRoot node is below the top of a tree.
+	ErrMisplacedRoot = errors.New("/root below the top of the tree")
+	// ErrLeftoverLeafHook is found by ValidateTree when a NKLeafHook node made it into the final tree.
+	ErrLeftoverLeafHook = errors.New("leftover /hook")
+	// ErrUnknownInfix is found by ValidateTree when an infix slot refers to a list that does not exist.
+	ErrUnknownInfix = errors.New("unknown infix list")
+	// ErrEmptyResultID is found by ValidateTree when a result node has no ID.
+	ErrEmptyResultID = errors.New("result without ID")
+)
+
+// ValidationProblem is a single problem found by ValidateTree.
+type ValidationProblem struct {
+	// SubTree is the name of the subtree the problem is in. It's empty for the root tree.
+	SubTree string
+	// Path lists the nodes from the top of the tree down to the problematic node.
+	Path []string
+	Err  error
+}
+
+func (problem *ValidationProblem) String() string {
+	sb := strings.Builder{}
+	if problem.SubTree != "" {
+		sb.WriteRune('$')
+		sb.WriteString(problem.SubTree)
+	} else {
+		sb.WriteString("root")
+	}
+
+	for _, step := range problem.Path {
+		sb.WriteString(" > ")
+		sb.WriteString(step)
+	}
+
+	sb.WriteString(": ")
+	sb.WriteString(problem.Err.Error())
+
+	return sb.String()
+}
+
+// ValidationError lists every problem found by ValidateTree.
+type ValidationError struct {
+	Problems []ValidationProblem
+}
+
+func (err *ValidationError) Error() string {
+	lines := make([]string, 0, len(err.Problems)+1)
+	lines = append(lines, fmt.Sprintf("%d problem(s) found in tree", len(err.Problems)))
+	for _, problem := range err.Problems {
+		lines = append(lines, problem.String())
+	}
+
+	return strings.Join(lines, "\n  ")
+}
+
+func (err *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(err.Problems))
+	for _, problem := range err.Problems {
+		errs = append(errs, problem.Err)
+	}
+
+	return errs
+}
+
+// Validate checks the root and all subtrees for problems that would otherwise show up as errors when
+// running, or silently give wrong results.
+func (dictionary *Dictionary) Validate() error {
+	subTreeMap := dictionary.SubTreeMap
+	if subTreeMap == nil {
+		subTreeMap = GenerateInitialSubTreeMap()
+	}
+
+	return validateTree(&dictionary.Root, subTreeMap, dictionary.compiledInfixMap())
+}
+
+// ValidateTree checks the tree and the subtree map for problems. It returns a *ValidationError listing
+// all of them, or nil if there are none.
+func ValidateTree(root *Node, subTreeMap map[string]*Node) error {
+	return validateTree(root, subTreeMap, infixMap)
+}
+
+func validateTree(root *Node, subTreeMap map[string]*Node, infixMap map[string][]infix) error {
+	v := treeValidator{subTreeMap: subTreeMap, infixMap: infixMap}
+	v.walk(root, nil, 0)
+
+	names := make([]string, 0, len(subTreeMap))
+	for name := range subTreeMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		v.subTree = name
+		v.walk(subTreeMap[name], nil, 0)
+	}
+
+	if len(v.problems) > 0 {
+		return &ValidationError{Problems: v.problems}
+	}
+
+	return nil
+}
+
+type treeValidator struct {
+	subTreeMap map[string]*Node
+	infixMap   map[string][]infix
+	subTree    string
+	problems   []ValidationProblem
+}
+
+func (v *treeValidator) walk(node *Node, path []string, depth int) {
+	if depth > 0 {
+		path = append(path, node.String())
+	}
+
+	switch node.Kind {
+	case NKRoot:
+		if depth > 0 {
+			v.report(path, ErrMisplacedRoot)
+		}
+	case NKResult:
+		if parseResultValue(node.Value).ID == "" {
+			v.report(path, ErrEmptyResultID)
+		}
+	case NKSubTree:
+		if v.subTreeMap[node.Value] == nil {
+			v.report(path, &SubTreeError{Name: node.Value, Err: ErrUnknownSubTree})
+		}
+	case NKReturn:
+		// The runner only knows where to return to once it has entered a subtree.
+		if v.subTree == "" {
+			v.report(path, ErrNowhereToReturn)
+		}
+	case NKLeafHook:
+		v.report(path, ErrLeftoverLeafHook)
+	case NKInfix:
+		// Only the first name is looked up, any other number is taken as a literal infix.
+		for i, name := range strings.Split(node.Value, ",") {
+			if name != "" && strings.Trim(name, "0123456789") == "" && (i > 0 || v.infixMap[name] == nil) {
+				v.report(path, fmt.Errorf("%w: %s", ErrUnknownInfix, name))
+			}
+		}
+	}
+
+	for i := range node.Children {
+		v.walk(&node.Children[i], path, depth+1)
+	}
+}
+
+func (v *treeValidator) report(path []string, err error) {
+	v.problems = append(v.problems, ValidationProblem{
+		SubTree: v.subTree,
+		Path:    append(path[:0:0], path...),
+		Err:     err,
+	})
+}
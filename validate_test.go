@@ -0,0 +1,48 @@
+package lutral
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDictionary_Validate(t *testing.T) {
+	dict := miniDict()
+	assert.NoError(t, dict.Validate())
+
+	dict.Optimize()
+	assert.NoError(t, dict.Validate())
+}
+
+func TestValidateTree(t *testing.T) {
+	root := CombineTrees(
+		BuildTree("$np", "uvan", "$nsmod|$nceq", "=2644"),
+		BuildTree("tìk", "/return"),
+		BuildTree("sìk", "/root", "=1796"),
+		BuildTree("ma", "/hook"),
+		BuildTree("k", "<0>", "<1>", "<2,3>", "ä", "=680"),
+		BuildTree("fm", "<4>", "i", "=:vtr."),
+		BuildTree("nga", "=@reef"),
+	)
+
+	subTrees := GenerateInitialSubTreeMap()
+	subTrees["np2"] = BuildTree("fne-", "$np3")
+
+	err := ValidateTree(root, subTrees)
+	assert.ErrorIs(t, err, ErrUnknownSubTree)
+	assert.ErrorIs(t, err, ErrNowhereToReturn)
+	assert.ErrorIs(t, err, ErrMisplacedRoot)
+	assert.ErrorIs(t, err, ErrLeftoverLeafHook)
+	assert.ErrorIs(t, err, ErrUnknownInfix)
+	assert.ErrorIs(t, err, ErrEmptyResultID)
+
+	assert.EqualError(t, err, `9 problem(s) found in tree
+  root > $np > uvan > $nceq: subtree $nceq: unknown subtree
+  root > tìk > /return: nowhere to /return to
+  root > sìk > /root: /root below the top of the tree
+  root > ma > /hook: leftover /hook
+  root > k > <0> > <1> > <2,3>: unknown infix list: 3
+  root > fm > <4>: unknown infix list: 4
+  root > fm > <4> > i > =:vtr.: result without ID
+  root > nga > =@reef: result without ID
+  $np2 > fne- > $np3: subtree $np3: unknown subtree`)
+}
@@ -9,25 +9,36 @@ func VerbFromEntry(entry Entry) *Node {
 	res := EmptyTree()
 
 	defaultResult := entry.ID
-	if len(entry.PoS) > 1 {
-		for _, pos := range entry.PoS {
-			if strings.HasPrefix(pos, "v") {
+	verbPoS := entry.PoS[0]
+	for _, pos := range entry.PoS {
+		if strings.HasPrefix(pos, "v") {
+			verbPoS = pos
+			if len(entry.PoS) > 1 {
 				defaultResult = entry.ID + ":" + pos
 			}
 		}
 	}
 
+	derived := func(pos, derivation string) string {
+		return derivedResultValue(entry.ID, pos, verbPoS, derivation)
+	}
+
 	hadSiPart := false
-	for _, siPart := range []string{" si", " säpi", " seyki", " säpeyki"} {
+	for _, siPart := range siParts {
 		if nounPart := strings.TrimSuffix(word, siPart); nounPart != word {
 			staticInfix0 := siPart[len(" s") : len(siPart)-len("i")]
 
 			res.MergeFrom(*GenerateSiVerb(nounPart, staticInfix0).AndThenResult(defaultResult))
-			res.MergeFrom(*GenerateSiVerbParticiple(nounPart, staticInfix0).AndThenResult(entry.ID + ":adj."))
-			res.MergeFrom(*GenerateSiVerbAgent(nounPart, staticInfix0).AndThenResult(entry.ID + ":n."))
-
-			if staticInfix0 == "" {
-				res.MergeFrom(*GenerateSiVerbTswo(nounPart).AndThenResult(entry.ID + ":n."))
+			if !entry.HasFlag("noderiv") {
+				res.MergeFrom(*GenerateSiVerbActiveParticiple(nounPart, staticInfix0).AndThenResult(derived("adj.", DerivationActiveParticiple)))
+				if passive := GenerateSiVerbPassiveParticiple(nounPart, staticInfix0); passive != nil {
+					res.MergeFrom(*passive.AndThenResult(derived("adj.", DerivationPassiveParticiple)))
+				}
+				res.MergeFrom(*GenerateSiVerbAgent(nounPart, staticInfix0).AndThenResult(derived("n.", DerivationAgent)))
+
+				if staticInfix0 == "" {
+					res.MergeFrom(*GenerateSiVerbTswo(nounPart).AndThenResult(derived("n.", DerivationAbility)))
+				}
 			}
 
 			hadSiPart = true
@@ -36,26 +47,58 @@ func VerbFromEntry(entry Entry) *Node {
 	}
 
 	if !hadSiPart {
-		res.MergeFrom(*GenerateVerb(word, *entry.InfixPositions).AndThenResult(defaultResult))
-		res.MergeFrom(*GenerateNegatedVerb(word, *entry.InfixPositions).AndThenResult(defaultResult))
-		res.MergeFrom(*GenerateVerbParticiple(word, *entry.InfixPositions).AndThenResult(entry.ID + ":adj."))
-		res.MergeFrom(*GenerateVerbTsuk(word, *entry.InfixPositions).AndThenResult(entry.ID + ":adj."))
-		res.MergeFrom(*GenerateVerbGerund(word, *entry.InfixPositions).AndThenResult(entry.ID + ":n."))
-		res.MergeFrom(*GenerateVerbTswo(word, *entry.InfixPositions).AndThenResult(entry.ID + ":n."))
-		res.MergeFrom(*GenerateVerbAgent(word, *entry.InfixPositions).AndThenResult(entry.ID + ":n."))
+		// The <er> and <ol> forms replacing rr and ll are only allowed if the syllable is unstressed.
+		stressed, _ := entry.IsStressedAt(entry.InfixPositions[0])
+
+		res.MergeFrom(*generateVerb(word, *entry.InfixPositions, stressed).AndThenResult(defaultResult))
+		res.MergeFrom(*generateNegatedVerb(word, *entry.InfixPositions, stressed).AndThenResult(defaultResult))
+		if !entry.HasFlag("noderiv") {
+			res.MergeFrom(*GenerateVerbActiveParticiple(word, *entry.InfixPositions).AndThenResult(derived("adj.", DerivationActiveParticiple)))
+			if passive := GenerateVerbPassiveParticiple(word, *entry.InfixPositions); passive != nil {
+				res.MergeFrom(*passive.AndThenResult(derived("adj.", DerivationPassiveParticiple)))
+			}
+			res.MergeFrom(*GenerateVerbTsuk(word, *entry.InfixPositions).AndThenResult(derived("adj.", DerivationTsukAdjective)))
+			res.MergeFrom(*GenerateVerbGerund(word, *entry.InfixPositions).AndThenResult(derived("n.", DerivationGerund)))
+			res.MergeFrom(*GenerateVerbTswo(word, *entry.InfixPositions).AndThenResult(derived("n.", DerivationAbility)))
+			res.MergeFrom(*GenerateVerbAgent(word, *entry.InfixPositions).AndThenResult(derived("n.", DerivationAgent)))
+			res.MergeFrom(*GenerateVerbInstrument(word).AndThenResult(derived("n.", DerivationInstrument)))
+		}
 	}
 
 	return res
 }
 
+// isSiVerb checks whether the word is a si-verb, which does not need infix positions.
+func isSiVerb(word string) bool {
+	for _, siPart := range siParts {
+		if strings.HasSuffix(word, siPart) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var siParts = []string{" si", " säpi", " seyki", " säpeyki"}
+
 func GenerateNegatedVerb(word string, infixes [2]int) *Node {
+	return generateNegatedVerb(word, infixes, false)
+}
+
+func generateNegatedVerb(word string, infixes [2]int, stressedInfix0 bool) *Node {
 	return CombineTrees(
-		BuildTree("[rä'ä]|[rää=rä'ä]|[ke]", " ").AndThen(*GenerateVerb(word, infixes)),
-		GenerateVerb(word, infixes).AndThen(*BuildTree(" ", "[rä'ä]|[rää=rä'ä]")),
+		BuildTree("[rä'ä]|[rää=rä'ä]|[ke]", " ").AndThen(*generateVerb(word, infixes, stressedInfix0)),
+		generateVerb(word, infixes, stressedInfix0).AndThen(*BuildTree(" ", "[rä'ä]|[rää=rä'ä]")),
 	)
 }
 
+// GenerateVerb generates the finite verb forms. It assumes any syllable may be unstressed, use
+// VerbFromEntry to take the entry's stress into account.
 func GenerateVerb(word string, infixes [2]int) *Node {
+	return generateVerb(word, infixes, false)
+}
+
+func generateVerb(word string, infixes [2]int, stressedInfix0 bool) *Node {
 	split3 := splitAtInfixes(word, infixes)
 
 	res := EmptyTree()
@@ -68,13 +111,13 @@ func GenerateVerb(word string, infixes [2]int) *Node {
 	}
 
 	if infixes[0] != infixes[1] {
-		// frrfen -> *ferfen (should only be allowed in unstressed syllables, but that info is not tracked)
-		if strings.HasPrefix(split3[1], "rr") {
+		// frrfen -> *ferfen (only allowed in unstressed syllables)
+		if strings.HasPrefix(split3[1], "rr") && !stressedInfix0 {
 			res.MergeFrom(*BuildTree(split3[0], "<0>", "<er>", strings.TrimPrefix(split3[1], "rr"), "<2>", split3[2]))
 		}
 
-		// plltxe -> poltxe (should only be allowed in unstressed syllables, but that info is not tracked)
-		if strings.HasPrefix(split3[1], "ll") {
+		// plltxe -> poltxe (only allowed in unstressed syllables)
+		if strings.HasPrefix(split3[1], "ll") && !stressedInfix0 {
 			res.MergeFrom(*BuildTree(split3[0], "<0>", "<ol>", strings.TrimPrefix(split3[1], "ll"), "<2>", split3[2]))
 		}
 
@@ -102,19 +145,43 @@ func GenerateVerb(word string, infixes [2]int) *Node {
 	return res
 }
 
+// GenerateVerbParticiple generates both participles, see GenerateVerbActiveParticiple and
+// GenerateVerbPassiveParticiple.
 func GenerateVerbParticiple(word string, infixes [2]int) *Node {
+	res := GenerateVerbActiveParticiple(word, infixes)
+	if passive := GenerateVerbPassiveParticiple(word, infixes); passive != nil {
+		res.MergeFrom(*passive)
+	}
+
+	return res
+}
+
+// GenerateVerbActiveParticiple generates the <us> participle (e.g. fmusetok).
+func GenerateVerbActiveParticiple(word string, infixes [2]int) *Node {
 	split2 := splitAtInfix(word, infixes[0])
 
-	if strings.HasSuffix(split2[0], "eyk") {
-		return GenerateAdjective(*BuildTree(split2[0], "<us,awn>", split2[1]))
-	} else if strings.HasSuffix(split2[0], "äp") {
+	if strings.HasSuffix(split2[0], "eyk") || strings.HasSuffix(split2[0], "äp") {
 		return GenerateAdjective(*BuildTree(split2[0], "<us>", split2[1]))
 	} else {
-		return GenerateAdjective(*BuildTree(split2[0], "<eyk,>", "<us,awn>", split2[1])).
+		return GenerateAdjective(*BuildTree(split2[0], "<eyk,>", "<us>", split2[1])).
 			MergedWith(*GenerateAdjective(*BuildTree(split2[0], "<äp>", "<us>", split2[1])))
 	}
 }
 
+// GenerateVerbPassiveParticiple generates the <awn> participle (e.g. fmawnetok). Verbs with a set-in-stone
+// <äp> have none, so it returns nil for them.
+func GenerateVerbPassiveParticiple(word string, infixes [2]int) *Node {
+	split2 := splitAtInfix(word, infixes[0])
+
+	if strings.HasSuffix(split2[0], "eyk") {
+		return GenerateAdjective(*BuildTree(split2[0], "<awn>", split2[1]))
+	} else if strings.HasSuffix(split2[0], "äp") {
+		return nil
+	} else {
+		return GenerateAdjective(*BuildTree(split2[0], "<eyk,>", "<awn>", split2[1]))
+	}
+}
+
 func GenerateVerbGerund(word string, infixes [2]int) *Node {
 	split2 := splitAtInfix(word, infixes[0])
 
@@ -155,6 +222,11 @@ func GenerateVerbAgent(word string, infixes [2]int) *Node {
 	}
 }
 
+// GenerateVerbInstrument generates the sä- noun for what is used to do the verb (e.g. säfpìl).
+func GenerateVerbInstrument(word string) *Node {
+	return GenerateNoun(*BuildTree("sä-", word))
+}
+
 func GenerateSiVerb(nounPart string, staticInfix0 string) *Node {
 	if staticInfix0 != "" {
 		return CombineTrees(
@@ -169,17 +241,41 @@ func GenerateSiVerb(nounPart string, staticInfix0 string) *Node {
 	}
 }
 
+// GenerateSiVerbParticiple generates both participles, see GenerateSiVerbActiveParticiple and
+// GenerateSiVerbPassiveParticiple.
 func GenerateSiVerbParticiple(nounPart string, staticInfix0 string) *Node {
+	res := GenerateSiVerbActiveParticiple(nounPart, staticInfix0)
+	if passive := GenerateSiVerbPassiveParticiple(nounPart, staticInfix0); passive != nil {
+		res.MergeFrom(*passive)
+	}
+
+	return res
+}
+
+// GenerateSiVerbActiveParticiple generates the <us> participle (e.g. uvan-susi).
+func GenerateSiVerbActiveParticiple(nounPart string, staticInfix0 string) *Node {
 	if staticInfix0 == "eyk" {
-		return GenerateAdjective(*BuildTree(nounPart, "\\-seyk", "<us,awn>", "i"))
+		return GenerateAdjective(*BuildTree(nounPart, "\\-seyk", "<us>", "i"))
 	} else if staticInfix0 == "äp" {
 		return GenerateAdjective(*BuildTree(nounPart, "\\-säp", "<us>", "i"))
 	} else {
-		return GenerateAdjective(*BuildTree(nounPart, "\\-s", "<eyk>", "<us,awn>", "i")).
+		return GenerateAdjective(*BuildTree(nounPart, "\\-s", "<eyk>", "<us>", "i")).
 			MergedWith(*GenerateAdjective(*BuildTree(nounPart, "\\-s", "<us>", "i")))
 	}
 }
 
+// GenerateSiVerbPassiveParticiple generates the <awn> participle (e.g. uvan-seykawni). There is none with a
+// set-in-stone <äp>, so it returns nil for them.
+func GenerateSiVerbPassiveParticiple(nounPart string, staticInfix0 string) *Node {
+	if staticInfix0 == "eyk" {
+		return GenerateAdjective(*BuildTree(nounPart, "\\-seyk", "<awn>", "i"))
+	} else if staticInfix0 == "äp" {
+		return nil
+	} else {
+		return GenerateAdjective(*BuildTree(nounPart, "\\-s", "<eyk>", "<awn>", "i"))
+	}
+}
+
 func GenerateSiVerbTswo(nounPart string) *Node {
 	return GenerateNoun(*BuildTree(nounPart, "-tswo"))
 }
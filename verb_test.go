@@ -37,6 +37,14 @@ func TestVerbFromEntry(t *testing.T) {
 			Entry: "392:fm<0><1>et<2>ok:vtr.", Test: "aketsukfmetok",
 			Expected: "392:adj. a-ketsuk-",
 		},
+		{
+			Entry: "392:fm<0><1>et<2>ok:vtr.", Test: "säfmetokit",
+			Expected: "392:n. sä- -it",
+		},
+		{
+			Entry: "392:fm<0><1>et<2>ok:vtr.", Test: "aysäfmetok",
+			Expected: "392:n. ay-sä-",
+		},
 		{
 			Entry: "392:fm<0><1>et<2>ok:vtr.", Test: "fmetoktswoori",
 			Expected: "392:n. -tswo-o-ri",
@@ -150,13 +158,29 @@ func TestVerbFromEntry(t *testing.T) {
 			Expected: "",
 		},
 		{
-			Entry: "464:f<0><1>rrf<2>en:vtr.", Test: "ferfen", // Incorrect, syllable is stressed, but I cannot find better example
+			Entry: "464:f<0><1>rrf<2>en:vtr.", Test: "ferfen", // Allowed since the entry has no stress marks, see below.
 			Expected: "464 <er>",
 		},
+		{
+			Entry: "464:ˈf<0><1>rr.f<2>en:vtr.", Test: "ferfen",
+			Expected: "",
+		},
+		{
+			Entry: "464:ˈf<0><1>rr.f<2>en:vtr.", Test: "frrfen",
+			Expected: "464",
+		},
 		{
 			Entry: "1544:p<0><1>lltx<2>e:vtr.", Test: "poltxe",
 			Expected: "1544 <ol>",
 		},
+		{
+			Entry: "1544:ˈp<0><1>ll.tx<2>e:vtr.", Test: "poltxe",
+			Expected: "",
+		},
+		{
+			Entry: "-1544:ˈp<0><1>ll.tx<2>e:vtr.", Test: "ke poltxe", // Fake entry to check the negated forms.
+			Expected: "",
+		},
 		{
 			Entry: "8852:v<0><1><2>ll:vtr.", Test: "vol",
 			Expected: "8852 <ol>",